@@ -0,0 +1,56 @@
+// Ackermann Benchmark - Go implementation
+// Output format: BENCH:ackermann:<test>:<result>:<time_ms>:<time_ns>
+//
+// The Ackermann function recurses far deeper than naive fib for the same
+// amount of work, exposing call overhead and stack growth.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func ack(m, n int64) int64 {
+	if m == 0 {
+		return n + 1
+	}
+	if n == 0 {
+		return ack(m-1, 1)
+	}
+	return ack(m-1, ack(m, n-1))
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	n := flag.Int64("n", 1000, "second argument for the ack-2-<n> test")
+	flag.Parse()
+	if *n < 0 {
+		fmt.Fprintf(os.Stderr, "ackermann: -n must not be negative, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	start := time.Now()
+	result := ack(3, 7)
+	elapsed := time.Since(start)
+	benchlib.Report("ackermann", "ack-3-7", result, elapsed)
+	if result != 1021 {
+		benchlib.ReportErr("ackermann", "ack-3-7", 1021, result)
+	}
+
+	// ack(2, n) = 2n + 3
+	test := fmt.Sprintf("ack-2-%d", *n)
+	start = time.Now()
+	result = ack(2, *n)
+	elapsed = time.Since(start)
+	benchlib.Report("ackermann", test, result, elapsed)
+	if expected := 2**n + 3; result != expected {
+		benchlib.ReportErr("ackermann", test, expected, result)
+	}
+}
@@ -0,0 +1,88 @@
+// Binary Trees Benchmark - Go implementation
+// Output format: BENCH:binarytrees:<test>:<result>:<time_ms>:<time_ns>
+//
+// The benchmarks game formulation: a stretch tree one level deeper than
+// -depth, a long-lived tree of -depth, and for every second depth from 4
+// up, many short-lived trees whose total node count stays roughly
+// constant. Nearly all the time goes to allocation and collection, making
+// this the GC-pressure counterpart to fibonacci. The result is the sum of
+// every tree's node count.
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const minDepth = 4
+
+type node struct {
+	left, right *node
+}
+
+func bottomUp(depth int) *node {
+	if depth <= 0 {
+		return &node{}
+	}
+	return &node{bottomUp(depth - 1), bottomUp(depth - 1)}
+}
+
+// check returns the number of nodes in the tree.
+func (n *node) check() int64 {
+	if n.left == nil {
+		return 1
+	}
+	return 1 + n.left.check() + n.right.check()
+}
+
+// iterations is how many trees of the given depth are built, keeping the
+// node count per depth roughly equal.
+func iterations(depth, maxDepth int) int {
+	return 1 << (maxDepth - depth + minDepth)
+}
+
+func run(maxDepth int) int64 {
+	total := bottomUp(maxDepth + 1).check()
+
+	longLived := bottomUp(maxDepth)
+	for depth := minDepth; depth <= maxDepth; depth += 2 {
+		for i := iterations(depth, maxDepth); i > 0; i-- {
+			total += bottomUp(depth).check()
+		}
+	}
+	return total + longLived.check()
+}
+
+// expected computes run's result arithmetically: a perfect tree of depth d
+// has 2^(d+1)-1 nodes.
+func expected(maxDepth int) int64 {
+	nodes := func(d int) int64 { return 1<<(d+1) - 1 }
+	total := nodes(maxDepth+1) + nodes(maxDepth)
+	for depth := minDepth; depth <= maxDepth; depth += 2 {
+		total += int64(iterations(depth, maxDepth)) * nodes(depth)
+	}
+	return total
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("gc")
+
+	depth := flag.Int("depth", 18, "maximum tree depth (at least 6)")
+	flag.Parse()
+	benchlib.ReportEnv()
+	maxDepth := max(*depth, minDepth+2)
+
+	start := time.Now()
+	total := run(maxDepth)
+	elapsed := time.Since(start)
+
+	test := "depth-" + benchlib.SizeName(int64(maxDepth))
+	benchlib.Report("binarytrees", test, total, elapsed)
+	if want := expected(maxDepth); total != want {
+		benchlib.ReportErr("binarytrees", test, want, total)
+		benchlib.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+// Cancel Benchmark - Go implementation
+// Output format: BENCH:cancel:<test>:<result>:<time_ms>:<time_ns>
+//
+// Builds a 10-ary tree of goroutines, each holding a context derived from
+// its parent's and blocked on ctx.Done(). Once every goroutine is waiting
+// the root is cancelled, and the timer covers only the fan-out until all
+// of them have observed it. Spawning the tree is not timed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const arity = 10
+
+type tree struct {
+	ready    sync.WaitGroup
+	done     sync.WaitGroup
+	observed atomic.Int64
+}
+
+// spawn starts a goroutine for a subtree of size goroutines rooted under
+// parent.
+func (t *tree) spawn(parent context.Context, size int64) {
+	go func() {
+		ctx, cancel := context.WithCancel(parent)
+		defer cancel()
+
+		rest := size - 1
+		for i := int64(0); i < arity && rest > 0; i++ {
+			child := (rest + arity - 1 - i) / (arity - i)
+			t.spawn(ctx, child)
+			rest -= child
+		}
+		t.ready.Done()
+
+		<-ctx.Done()
+		t.observed.Add(1)
+		t.done.Done()
+	}()
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int64("n", 100000, "number of goroutines in the tree")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	flag.Parse()
+	benchlib.ReportEnv()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "cancel: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	var t tree
+	t.ready.Add(int(*n))
+	t.done.Add(int(*n))
+
+	root, cancel := context.WithCancel(context.Background())
+	t.spawn(root, *n)
+	t.ready.Wait()
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	cancel()
+	t.done.Wait()
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	test := "propagate-" + benchlib.SizeName(*n)
+	observed := t.observed.Load()
+	benchlib.ReportWithCPU("cancel", test, observed, elapsed, cpuTime)
+	if observed != *n {
+		benchlib.ReportErr("cancel", test, *n, observed)
+		benchlib.Exit(1)
+	}
+	leaks.Verify("cancel")
+}
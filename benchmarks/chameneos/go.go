@@ -0,0 +1,171 @@
+// Chameneos Benchmark - Go implementation
+// Output format: BENCH:chameneos:<test>:<result>:<time_ms>:<time_ns>
+//
+// Chameneos-redux: creatures of three colors repeatedly ask a broker
+// goroutine for a meeting. The broker pairs requests off, each creature
+// learns its partner's color and takes the complement of the two (its own
+// color if they match, otherwise the third), until -n meetings (600,000 by
+// default) have happened. meet-3 runs the standard three-creature game
+// (blue, red, yellow) and meet-10 the ten-creature one. Every meeting
+// involves two creatures, so the result, the sum of the creatures' meeting
+// counts, must be 2n (the spelled-out total of the reference output); no
+// creature may ever meet itself, and the complement rules are checked
+// against the standard table first.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+type color int
+
+const (
+	blue color = iota
+	red
+	yellow
+)
+
+// complement is the color a creature takes after meeting one of color b.
+func (a color) complement(b color) color {
+	if a == b {
+		return a
+	}
+	// the three colors are 0, 1 and 2, so the missing one is 3 - a - b
+	return 3 - a - b
+}
+
+// complements is the standard color-complement table, checked against
+// complement before the games run.
+var complements = [3][3]color{
+	blue:   {blue: blue, red: yellow, yellow: red},
+	red:    {blue: yellow, red: red, yellow: blue},
+	yellow: {blue: red, red: blue, yellow: yellow},
+}
+
+var colorNames = [...]string{blue: "blue", red: "red", yellow: "yellow"}
+
+// games are the standard starting populations.
+var games = [][]color{
+	{blue, red, yellow},
+	{blue, red, yellow, red, yellow, blue, red, yellow, red, blue},
+}
+
+// request asks the broker for a meeting; the partner's details come back
+// on reply, or done once all meetings have happened.
+type request struct {
+	id    int
+	color color
+	reply chan partner
+}
+
+type partner struct {
+	id    int
+	color color
+	done  bool
+}
+
+// tally is one creature's result.
+type tally struct {
+	meetings, self int64
+}
+
+// broker pairs requests until n meetings have happened, then answers one
+// more request from each of the creatures with done.
+func broker(requests <-chan request, n int64, creatures int) {
+	for i := int64(0); i < n; i++ {
+		a := <-requests
+		b := <-requests
+		a.reply <- partner{id: b.id, color: b.color}
+		b.reply <- partner{id: a.id, color: a.color}
+	}
+	for i := 0; i < creatures; i++ {
+		r := <-requests
+		r.reply <- partner{done: true}
+	}
+}
+
+func creature(id int, c color, requests chan<- request, results chan<- tally) {
+	var t tally
+	reply := make(chan partner)
+	for {
+		requests <- request{id: id, color: c, reply: reply}
+		p := <-reply
+		if p.done {
+			results <- t
+			return
+		}
+		t.meetings++
+		if p.id == id {
+			t.self++
+		}
+		c = c.complement(p.color)
+	}
+}
+
+// play runs one game and returns the total meetings counted by the
+// creatures and how many of those were with themselves.
+func play(colors []color, n int64) (meetings, self int64) {
+	requests := make(chan request)
+	results := make(chan tally)
+	go broker(requests, n, len(colors))
+	for id, c := range colors {
+		go creature(id, c, requests, results)
+	}
+	for range colors {
+		t := <-results
+		meetings += t.meetings
+		self += t.self
+	}
+	return meetings, self
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int64("n", 600000, "number of meetings per game")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "chameneos: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	for a := range complements {
+		for b, want := range complements[a] {
+			if got := color(a).complement(color(b)); got != want {
+				benchlib.Failf("chameneos", "complement", "%s + %s -> %s, want %s",
+					colorNames[a], colorNames[b], colorNames[got], colorNames[want])
+			}
+		}
+	}
+
+	for _, colors := range games {
+		test := fmt.Sprintf("meet-%d", len(colors))
+		cpu := benchlib.StartCPU()
+		start := time.Now()
+		meetings, self := play(colors, *n)
+		elapsed := time.Since(start)
+		cpuTime := cpu.Stop()
+
+		benchlib.ReportWithCPU("chameneos", test, meetings, elapsed, cpuTime)
+		if meetings != 2**n {
+			benchlib.ReportErr("chameneos", test, 2**n, meetings)
+		}
+		if self != 0 {
+			benchlib.Failf("chameneos", test, "%d meetings of a creature with itself", self)
+		}
+	}
+	leaks.Verify("chameneos")
+}
@@ -0,0 +1,109 @@
+// Channel Benchmark - Go implementation
+// Output format: BENCH:channel:<test>:<result>:<time_ms>:<time_ns>
+//
+// Sends -n values (1,000,000 by default) into a channel that one goroutine
+// drains in a tight loop, once with a buffered channel (capacity 100) and
+// once unbuffered. Unlike pingpong the sender never waits for a reply, so
+// this measures raw send/receive cost rather than round trips. The result
+// is the sum of the received values, and a -throughput line reports
+// operations per second.
+//
+// churn-<n> instead makes n short-lived channels, each carrying a single
+// value before it is dropped for the GC, so it measures channel allocation
+// rather than traffic on one channel. Its -throughput line reports
+// channels per second.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const bufferSize = 100
+
+// sendAll sends 0..n-1 on ch while a drain goroutine receives them, and
+// returns the drained sum once the drain has seen every value.
+func sendAll(ch chan int64, n int64) int64 {
+	done := make(chan int64)
+	go func() {
+		var sum int64
+		for i := int64(0); i < n; i++ {
+			sum += <-ch
+		}
+		done <- sum
+	}()
+	for i := int64(0); i < n; i++ {
+		ch <- i
+	}
+	return <-done
+}
+
+// churn makes n one-slot channels, sends i through the i'th and receives
+// it back, and returns the sum of the received values.
+func churn(n int64) int64 {
+	var sum int64
+	for i := int64(0); i < n; i++ {
+		ch := make(chan int64, 1)
+		ch <- i
+		sum += <-ch
+	}
+	return sum
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int64("n", 1000000, "number of values to send")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "channel: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	expected := *n * (*n - 1) / 2
+	for _, tc := range []struct {
+		test     string
+		capacity int
+	}{
+		{"buffered-send", bufferSize},
+		{"unbuffered-send", 0},
+	} {
+		ch := make(chan int64, tc.capacity)
+		cpu := benchlib.StartCPU()
+		start := time.Now()
+		sum := sendAll(ch, *n)
+		elapsed := time.Since(start)
+		cpuTime := cpu.Stop()
+
+		benchlib.ReportWithCPU("channel", tc.test, sum, elapsed, cpuTime)
+		benchlib.ReportRate("channel", tc.test, *n, "ops", elapsed)
+		if sum != expected {
+			benchlib.ReportErr("channel", tc.test, expected, sum)
+		}
+	}
+
+	test := "churn-" + benchlib.SizeName(*n)
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	sum := churn(*n)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+	benchlib.ReportWithCPU("channel", test, sum, elapsed, cpuTime)
+	benchlib.ReportRate("channel", test, *n, "channels", elapsed)
+	if sum != expected {
+		benchlib.ReportErr("channel", test, expected, sum)
+	}
+	leaks.Verify("channel")
+}
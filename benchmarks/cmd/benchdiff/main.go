@@ -0,0 +1,211 @@
+// Command benchdiff compares two result files saved by runall -json and
+// prints each test's old and new time with the percent change, largest
+// regression first, followed by a summary:
+//
+//	./bin/runall -json=old.json
+//	# ...make a change, rebuild...
+//	./bin/runall -json=new.json
+//	go run ./cmd/benchdiff old.json new.json
+//
+// Unlike runall -baseline it never fails on a slowdown; it's for checking
+// by hand whether a change helped. Changes within -threshold percent count
+// as unchanged. Tests found in only one file are listed after the others.
+// meta records, and lines that restate another test's time (-throughput,
+// -per-op, the -min and -max of a multi-run test, latency percentiles),
+// are left out.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// derivedSuffixes mark lines that restate the time of another line.
+var derivedSuffixes = []string{
+	"-throughput", "-per-op", "-min", "-max",
+	"-p50", "-p90", "-p99", "-p99.9",
+}
+
+// row is one test's change between the two files. old or cur is nil when
+// the test is in only one of them.
+type row struct {
+	key      string
+	old, cur *benchlib.Record
+	// pct is the percent change in time, positive meaning slower. hasPct
+	// is false when it can't be computed: a record is missing or the old
+	// one took no measurable time.
+	pct    float64
+	hasPct bool
+}
+
+func key(r benchlib.Record) string {
+	return r.Category + ":" + r.Test
+}
+
+func load(path string) ([]benchlib.Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recs []benchlib.Record
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return recs, nil
+}
+
+// index maps the comparable records of recs by key.
+func index(recs []benchlib.Record) map[string]benchlib.Record {
+	byKey := make(map[string]benchlib.Record, len(recs))
+	for _, r := range recs {
+		if r.Category == "meta" || slices.ContainsFunc(derivedSuffixes, func(s string) bool {
+			return strings.HasSuffix(r.Test, s)
+		}) {
+			continue
+		}
+		byKey[key(r)] = r
+	}
+	return byKey
+}
+
+// elapsed picks the most precise time both records carry: time_ns when
+// each has it, since time_ms is often 0 for fast tests.
+func elapsed(old, cur benchlib.Record) (o, c int64) {
+	if old.TimeNs > 0 && cur.TimeNs > 0 {
+		return old.TimeNs, cur.TimeNs
+	}
+	return old.TimeMs, cur.TimeMs
+}
+
+// diff pairs up the tests of old and cur. Tests in both come first,
+// largest regression first, then those without a percent change; tests in
+// only one file follow, each group sorted by key.
+func diff(old, cur []benchlib.Record) []row {
+	oldKeys, curKeys := index(old), index(cur)
+	var both, oneSided []row
+	for k, o := range oldKeys {
+		c, ok := curKeys[k]
+		if !ok {
+			oneSided = append(oneSided, row{key: k, old: &o})
+			continue
+		}
+		r := row{key: k, old: &o, cur: &c}
+		if ot, ct := elapsed(o, c); ot > 0 {
+			r.pct = float64(ct-ot) / float64(ot) * 100
+			r.hasPct = true
+		}
+		both = append(both, r)
+	}
+	for k, c := range curKeys {
+		if _, ok := oldKeys[k]; !ok {
+			oneSided = append(oneSided, row{key: k, cur: &c})
+		}
+	}
+	sort.Slice(both, func(i, j int) bool {
+		a, b := both[i], both[j]
+		if a.hasPct != b.hasPct {
+			return a.hasPct
+		}
+		if a.pct != b.pct {
+			return a.pct > b.pct
+		}
+		return a.key < b.key
+	})
+	sort.Slice(oneSided, func(i, j int) bool {
+		// Removed tests before added ones
+		if (oneSided[i].cur == nil) != (oneSided[j].cur == nil) {
+			return oneSided[i].cur == nil
+		}
+		return oneSided[i].key < oneSided[j].key
+	})
+	return append(both, oneSided...)
+}
+
+// formatTime renders a record's time, in nanosecond precision when it has
+// it.
+func formatTime(r *benchlib.Record) string {
+	switch {
+	case r == nil:
+		return "-"
+	case r.TimeNs > 0:
+		return time.Duration(r.TimeNs).String()
+	default:
+		return (time.Duration(r.TimeMs) * time.Millisecond).String()
+	}
+}
+
+// write prints the comparison table and summary, treating changes within
+// threshold percent as unchanged.
+func write(w io.Writer, rows []row, threshold float64) error {
+	var improved, regressed, unchanged, removed, added int
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tOLD\tNEW\tDELTA\t")
+	for _, r := range rows {
+		delta := "n/a"
+		switch {
+		case r.cur == nil:
+			delta = "removed"
+			removed++
+		case r.old == nil:
+			delta = "added"
+			added++
+		case r.hasPct:
+			delta = fmt.Sprintf("%+.1f%%", r.pct)
+			switch {
+			case r.pct > threshold:
+				regressed++
+			case r.pct < -threshold:
+				improved++
+			default:
+				unchanged++
+			}
+		default:
+			unchanged++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", r.key, formatTime(r.old), formatTime(r.cur), delta)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d improved, %d regressed, %d within %.0f%%, %d only in old, %d only in new\n",
+		improved, regressed, unchanged, threshold, removed, added)
+	return err
+}
+
+func main() {
+	threshold := flag.Float64("threshold", 5, "percent change within which a test counts as unchanged")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: benchdiff [-threshold=pct] old.json new.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	old, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchdiff: %v\n", err)
+		os.Exit(2)
+	}
+	cur, err := load(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchdiff: %v\n", err)
+		os.Exit(2)
+	}
+	if err := write(os.Stdout, diff(old, cur), *threshold); err != nil {
+		fmt.Fprintf(os.Stderr, "benchdiff: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func rec(category, test string, ms, ns int64) benchlib.Record {
+	return benchlib.Record{Category: category, Test: test, TimeMs: ms, TimeNs: ns}
+}
+
+func TestDiff(t *testing.T) {
+	old := []benchlib.Record{
+		rec("meta", "gomaxprocs", 0, 0),
+		rec("primes", "count-10k", 0, 1000),
+		rec("primes", "count-100k", 10, 0),
+		rec("primes", "gone", 5, 0),
+		rec("skynet", "spawn-100k", 100, 0),
+		rec("skynet", "spawn-100k-throughput", 100, 0),
+		rec("sync", "cas-counter-retries", 0, 0),
+	}
+	cur := []benchlib.Record{
+		rec("meta", "gomaxprocs", 0, 0),
+		rec("primes", "count-10k", 0, 1200),
+		rec("primes", "count-100k", 8, 0),
+		rec("primes", "new", 1, 0),
+		rec("skynet", "spawn-100k", 150, 0),
+		rec("skynet", "spawn-100k-throughput", 150, 0),
+		rec("sync", "cas-counter-retries", 0, 0),
+	}
+
+	rows := diff(old, cur)
+	var keys []string
+	for _, r := range rows {
+		keys = append(keys, r.key)
+	}
+	want := []string{
+		"skynet:spawn-100k", "primes:count-10k", "primes:count-100k",
+		"sync:cas-counter-retries", "primes:gone", "primes:new",
+	}
+	if strings.Join(keys, " ") != strings.Join(want, " ") {
+		t.Fatalf("diff order = %v, want %v", keys, want)
+	}
+	if rows[0].pct != 50 || rows[1].pct != 20 || rows[2].pct != -20 {
+		t.Errorf("pcts = %v, %v, %v; want 50, 20, -20 (count-10k in ns)", rows[0].pct, rows[1].pct, rows[2].pct)
+	}
+	if rows[3].hasPct {
+		t.Errorf("a test with no old time has a percent change: %+v", rows[3])
+	}
+	if rows[4].cur != nil || rows[5].old != nil {
+		t.Errorf("one-sided rows = %+v, %+v", rows[4], rows[5])
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	rows := diff(
+		[]benchlib.Record{rec("a", "slower", 10, 0), rec("a", "faster", 10, 0), rec("a", "same", 10, 0), rec("a", "gone", 1, 0)},
+		[]benchlib.Record{rec("a", "slower", 20, 0), rec("a", "faster", 5, 0), rec("a", "same", 10, 0), rec("a", "new", 1, 0)},
+	)
+	var b strings.Builder
+	if err := write(&b, rows, 5); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if want := "1 improved, 1 regressed, 1 within 5%, 1 only in old, 1 only in new\n"; !strings.HasSuffix(out, want) {
+		t.Errorf("output ends %q, want summary %q", out, want)
+	}
+	for _, s := range []string{"+100.0%", "-50.0%", "removed", "added"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output lacks %q:\n%s", s, out)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// comparison is one test's timing against the baseline.
+type comparison struct {
+	key       string
+	base, cur benchlib.Record
+	// pct is the percent change in time; positive means slower. For a
+	// multi-run test it compares the -med (p50) lines.
+	pct       float64
+	regressed bool
+	// noise marks a multi-run test slower than the threshold whose
+	// baseline median still lies within the current run's -min to -max
+	// spread, so the change isn't treated as a regression.
+	noise bool
+}
+
+func key(r benchlib.Record) string {
+	return r.Category + ":" + r.Test
+}
+
+func saveJSON(path string, recs []benchlib.Record) error {
+	data, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func loadBaseline(path string) ([]benchlib.Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recs []benchlib.Record
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+// elapsed picks the most precise time both records carry: time_ns when
+// each has it, since time_ms is often 0 for fast tests.
+func elapsed(base, cur benchlib.Record) (b, c int64) {
+	if base.TimeNs > 0 && cur.TimeNs > 0 {
+		return base.TimeNs, cur.TimeNs
+	}
+	return base.TimeMs, cur.TimeMs
+}
+
+// spread is the -min to -max range of a multi-run test.
+type spread struct {
+	min, max benchlib.Record
+}
+
+// contains reports whether t lies within the spread, comparing in
+// nanoseconds when ns is set and milliseconds otherwise, to match the
+// units elapsed chose for t.
+func (sp spread) contains(t int64, ns bool) bool {
+	if ns {
+		return t >= sp.min.TimeNs && t <= sp.max.TimeNs
+	}
+	return t >= sp.min.TimeMs && t <= sp.max.TimeMs
+}
+
+// spreads indexes the -min and -max records of multi-run tests by the
+// key of their -med record.
+func spreads(recs []benchlib.Record) map[string]spread {
+	byKey := make(map[string]benchlib.Record, len(recs))
+	for _, r := range recs {
+		byKey[key(r)] = r
+	}
+	out := make(map[string]spread)
+	for k, r := range byKey {
+		group, ok := strings.CutSuffix(k, "-med")
+		if !ok {
+			continue
+		}
+		lo, okLo := byKey[group+"-min"]
+		hi, okHi := byKey[group+"-max"]
+		if okLo && okHi {
+			out[key(r)] = spread{lo, hi}
+		}
+	}
+	return out
+}
+
+// runGroup returns the -med key that a -min or -max key belongs to.
+func runGroup(k string) (string, bool) {
+	for _, suffix := range []string{"-min", "-max"} {
+		if group, ok := strings.CutSuffix(k, suffix); ok {
+			return group + "-med", true
+		}
+	}
+	return "", false
+}
+
+// compare matches current records against the baseline. A test regresses
+// when it is more than threshold percent slower. Baseline tests whose
+// category contains filter, and is in selected when selected is non-nil,
+// but which are absent from current are returned as missing; the rest
+// weren't selected to run. meta records describe the machine, not a
+// timing, and are skipped.
+//
+// Multi-run tests are judged on their medians rather than every line: the
+// -med lines give the percent change, and a change beyond threshold only
+// counts as a regression when the baseline median falls outside the
+// current run's -min to -max range. Otherwise it is marked as noise. The
+// -min and -max lines of such tests aren't compared on their own.
+func compare(current, baseline []benchlib.Record, threshold float64, filter string, selected map[string]bool) (cmps []comparison, missing []string) {
+	byKey := make(map[string]benchlib.Record, len(current))
+	for _, r := range current {
+		byKey[key(r)] = r
+	}
+	curSpread := spreads(current)
+	baseSpread := spreads(baseline)
+	for _, base := range baseline {
+		if base.Category == "meta" || !strings.Contains(base.Category, filter) ||
+			selected != nil && !selected[base.Category] {
+			continue
+		}
+		cur, ok := byKey[key(base)]
+		if !ok {
+			missing = append(missing, key(base))
+			continue
+		}
+		if med, ok := runGroup(key(base)); ok {
+			if _, grouped := curSpread[med]; grouped {
+				if _, grouped := baseSpread[med]; grouped {
+					continue
+				}
+			}
+		}
+		b, c := elapsed(base, cur)
+		if b == 0 {
+			// No measurable baseline time to compare against
+			continue
+		}
+		pct := float64(c-b) / float64(b) * 100
+		cmp := comparison{
+			key:       key(base),
+			base:      base,
+			cur:       cur,
+			pct:       pct,
+			regressed: pct > threshold,
+		}
+		if sp, ok := curSpread[key(base)]; ok && cmp.regressed {
+			if _, ok := baseSpread[key(base)]; ok && sp.contains(b, base.TimeNs > 0 && cur.TimeNs > 0) {
+				cmp.regressed = false
+				cmp.noise = true
+			}
+		}
+		cmps = append(cmps, cmp)
+	}
+	return cmps, missing
+}
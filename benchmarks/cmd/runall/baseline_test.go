@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func rec(category, test string, ms, ns int64) benchlib.Record {
+	return benchlib.Record{Category: category, Test: test, TimeMs: ms, TimeNs: ns}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []benchlib.Record{
+		rec("meta", "gomaxprocs", 0, 0),
+		rec("primes", "count-10k", 0, 1000),
+		rec("primes", "count-100k", 10, 0),
+		rec("primes", "sieve-100k", 0, 0),
+		rec("primes", "gone", 5, 0),
+		rec("skynet", "spawn-100k", 100, 0),
+	}
+	current := []benchlib.Record{
+		rec("meta", "gomaxprocs", 0, 0),
+		rec("primes", "count-10k", 0, 1200),
+		rec("primes", "count-100k", 10, 0),
+		rec("primes", "sieve-100k", 1, 0),
+		rec("skynet", "spawn-100k", 105, 0),
+	}
+
+	cmps, missing := compare(current, baseline, 10, "", nil)
+
+	got := map[string]comparison{}
+	for _, c := range cmps {
+		got[c.key] = c
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d comparisons, want 3: %+v", len(got), cmps)
+	}
+	if c := got["primes:count-10k"]; !c.regressed || c.pct != 20 {
+		t.Errorf("count-10k = %+v, want a 20%% regression measured in ns", c)
+	}
+	if c := got["primes:count-100k"]; c.regressed || c.pct != 0 {
+		t.Errorf("count-100k = %+v, want unchanged", c)
+	}
+	if c := got["skynet:spawn-100k"]; c.regressed || c.pct != 5 {
+		t.Errorf("spawn-100k = %+v, want a 5%% change within threshold", c)
+	}
+	if len(missing) != 1 || missing[0] != "primes:gone" {
+		t.Errorf("missing = %v, want [primes:gone]", missing)
+	}
+}
+
+func TestCompareFilterLimitsMissing(t *testing.T) {
+	baseline := []benchlib.Record{
+		rec("primes", "count-10k", 1, 0),
+		rec("skynet", "spawn-100k", 100, 0),
+	}
+	current := []benchlib.Record{rec("primes", "count-10k", 1, 0)}
+
+	_, missing := compare(current, baseline, 10, "primes", nil)
+	if len(missing) != 0 {
+		t.Errorf("missing = %v, want none for filtered-out categories", missing)
+	}
+}
+
+func TestCompareTagsLimitsMissing(t *testing.T) {
+	// As runall -tags=gc -baseline=... with only wordcount selected
+	selected := map[string]bool{"wordcount": true}
+	baseline := []benchlib.Record{
+		rec("wordcount", "count-200k", 10, 0),
+		rec("primes", "count-10k", 1, 0),
+		rec("skynet", "spawn-100k", 100, 0),
+	}
+	current := []benchlib.Record{rec("wordcount", "count-200k", 10, 0)}
+
+	cmps, missing := compare(current, baseline, 10, "", selected)
+	if len(missing) != 0 {
+		t.Errorf("missing = %v, want none for untagged benchmarks", missing)
+	}
+	if len(cmps) != 1 || cmps[0].key != "wordcount:count-200k" {
+		t.Errorf("cmps = %+v, want only wordcount:count-200k", cmps)
+	}
+
+	_, missing = compare(nil, baseline, 10, "", selected)
+	if len(missing) != 1 || missing[0] != "wordcount:count-200k" {
+		t.Errorf("missing = %v, want [wordcount:count-200k] when the tagged benchmark didn't run", missing)
+	}
+}
+
+func TestCompareMultiRunUsesMedianAndSpread(t *testing.T) {
+	baseline := []benchlib.Record{
+		rec("primes", "count-10k-min", 0, 900),
+		rec("primes", "count-10k-med", 0, 1000),
+		rec("primes", "count-10k-max", 0, 1100),
+		rec("primes", "sieve-100k-min", 0, 900),
+		rec("primes", "sieve-100k-med", 0, 1000),
+		rec("primes", "sieve-100k-max", 0, 1100),
+	}
+	current := []benchlib.Record{
+		// median 30% slower but a wide spread covering the old median
+		rec("primes", "count-10k-min", 0, 950),
+		rec("primes", "count-10k-med", 0, 1300),
+		rec("primes", "count-10k-max", 0, 3000),
+		// median 30% slower and every run slower than the old median
+		rec("primes", "sieve-100k-min", 0, 1250),
+		rec("primes", "sieve-100k-med", 0, 1300),
+		rec("primes", "sieve-100k-max", 0, 1400),
+	}
+
+	cmps, missing := compare(current, baseline, 10, "", nil)
+	if len(missing) != 0 {
+		t.Fatalf("missing = %v, want none", missing)
+	}
+	got := map[string]comparison{}
+	for _, c := range cmps {
+		got[c.key] = c
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d comparisons, want only the two -med lines: %+v", len(got), cmps)
+	}
+	if c := got["primes:count-10k-med"]; c.regressed || !c.noise || c.pct != 30 {
+		t.Errorf("count-10k-med = %+v, want a 30%% change labelled noise", c)
+	}
+	if c := got["primes:sieve-100k-med"]; !c.regressed || c.noise {
+		t.Errorf("sieve-100k-med = %+v, want a regression", c)
+	}
+}
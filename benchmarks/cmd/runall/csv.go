@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// csvSink writes records to a CSV file as each benchmark finishes, so the
+// rows collected so far survive a later benchmark failing.
+type csvSink struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func createCSV(path string) (*csvSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &csvSink{f: f, w: csv.NewWriter(f)}
+	if err := s.w.Write([]string{"category", "test", "result", "time_ms"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// write appends one row per record and flushes them to the file.
+func (s *csvSink) write(recs []benchlib.Record) error {
+	for _, r := range recs {
+		row := []string{
+			r.Category,
+			r.Test,
+			strconv.FormatInt(r.Result, 10),
+			strconv.FormatInt(r.TimeMs, 10),
+		}
+		if err := s.w.Write(row); err != nil {
+			return err
+		}
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *csvSink) Close() error {
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,340 @@
+// Command runall runs every built Go benchmark binary and prints one
+// consolidated table of their BENCH results.
+//
+// Build the benchmarks into a directory first, then point runall at it:
+//
+//	go build -o bin/ ./...
+//	./bin/runall -dir=bin -filter=primes -csv=results.csv
+//
+// Every executable in -dir is treated as a benchmark (runall skips
+// itself, benchdiff, and the combined compute binary, whose benchmarks
+// each have their own). It exits non-zero if any benchmark does, or runs
+// longer than -timeout; a timed-out benchmark's process group is killed
+// and the rest of the suite carries on. A benchmark announcing a newer
+// line format than runall reads fails too, and none of its results are
+// kept.
+//
+// -tags=cpu,gc runs only the binaries that declare one of those tags,
+// which each reports when run with BENCH_LIST_TAGS=1.
+// With -baseline, only the baseline tests of those benchmarks must run.
+//
+// -md writes the results as a Markdown table for pasting into a pull
+// request, with a vs-baseline column and regressed rows in bold when
+// -baseline is given.
+//
+// -json saves the results for use as a later -baseline. With -baseline,
+// runall also exits non-zero if any test is more than -threshold percent
+// slower than the baseline, or if a baseline test didn't run.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// tools are commands built into the same directory as the benchmarks that
+// runall doesn't run as benchmarks: benchdiff isn't one, and compute
+// reruns every benchmark that already has its own binary.
+var tools = map[string]bool{"benchdiff": true, "compute": true}
+
+// discover returns the executables in dir whose names contain filter,
+// other than runall itself and the tools.
+func discover(dir, filter string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	self, _ := os.Executable()
+	var bins []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.Contains(e.Name(), filter) ||
+			tools[strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))] {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if abs, err := filepath.Abs(path); err == nil && abs == self {
+			continue
+		}
+		bins = append(bins, path)
+	}
+	sort.Strings(bins)
+	return bins, nil
+}
+
+// withTags keeps the binaries that declare one of tags, and returns the
+// names of the benchmarks kept. A binary that can't say what its tags are
+// is dropped with a warning.
+func withTags(bins, tags []string) (kept []string, selected map[string]bool) {
+	selected = make(map[string]bool)
+	for _, bin := range bins {
+		declared, err := binTags(bin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "runall: warning: %s: %v; skipping it\n", filepath.Base(bin), err)
+			continue
+		}
+		for _, t := range declared {
+			if slices.Contains(tags, t) {
+				kept = append(kept, bin)
+				selected[strings.TrimSuffix(filepath.Base(bin), filepath.Ext(bin))] = true
+				break
+			}
+		}
+	}
+	return kept, selected
+}
+
+// binTags runs bin with BENCH_LIST_TAGS=1 and returns the tags it prints;
+// see benchlib.Declare. A binary built before tags were declared runs its
+// benchmark instead, so its output isn't a tag line and is rejected.
+func binTags(bin string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin)
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(), "BENCH_LIST_TAGS=1")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	line := strings.TrimSpace(string(out))
+	if strings.ContainsAny(line, ":\n") {
+		return nil, errors.New("doesn't declare its tags")
+	}
+	if line == "" {
+		return nil, nil
+	}
+	return strings.Split(line, ","), nil
+}
+
+// errTimeout reports a benchmark killed for running past -timeout.
+var errTimeout = errors.New("timed out")
+
+// run executes one benchmark, killing it and its process group if it runs
+// longer than timeout, and returns the records it printed along with the
+// category:test keys of any ERROR lines.
+func run(bin string, timeout time.Duration) (recs []benchlib.Record, failedTests []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin)
+	killGroupOnCancel(cmd)
+	// Don't hang on output pipes held open by a killed child's descendants
+	cmd.WaitDelay = time.Second
+	// Children must print the colon format for us to parse
+	cmd.Env = append(os.Environ(), "BENCH_FORMAT=")
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("%w after %v", errTimeout, timeout)
+	}
+
+	recs, failedTests, schemaErr := parseOutput(filepath.Base(bin), &stdout)
+	if schemaErr != nil {
+		return nil, failedTests, schemaErr
+	}
+	return recs, failedTests, err
+}
+
+// parseOutput reads the records and ERROR keys from the output of the
+// benchmark name. A schema header newer than SchemaVersion discards all of
+// its records, including those printed before the header, and is returned
+// as an error so the benchmark counts as failed.
+func parseOutput(name string, out io.Reader) (recs []benchlib.Record, failedTests []string, err error) {
+	var env *benchlib.Env
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		if k, ok := errorKey(scanner.Text()); ok {
+			failedTests = append(failedTests, k)
+			continue
+		}
+		if e, err := benchlib.ParseEnv(scanner.Text()); err == nil {
+			env = &e
+			continue
+		}
+		rec, err := benchlib.ParseLine(scanner.Text())
+		switch {
+		case errors.Is(err, benchlib.ErrNotBench):
+			continue
+		case errors.Is(err, benchlib.ErrSchema):
+			return nil, failedTests, err
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "runall: %s: %v\n", name, err)
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	for i := range recs {
+		recs[i].Env = env
+	}
+	return recs, failedTests, nil
+}
+
+func main() {
+	os.Exit(runAll())
+}
+
+// runAll does the work of main and returns the exit code, so deferred
+// cleanup such as closing the CSV file runs before the process exits.
+func runAll() int {
+	dir := flag.String("dir", "bin", "directory containing built benchmark binaries")
+	filter := flag.String("filter", "", "only run binaries whose name contains this substring")
+	tagList := flag.String("tags", "", "only run benchmarks declaring one of these comma-separated tags")
+	timeout := flag.Duration("timeout", 60*time.Second, "kill a benchmark that runs longer than this")
+	csvPath := flag.String("csv", "", "also write results to this CSV file")
+	jsonPath := flag.String("json", "", "also write results to this JSON file")
+	mdPath := flag.String("md", "", "also write results to this file as a Markdown table")
+	baselinePath := flag.String("baseline", "", "JSON results to compare against")
+	threshold := flag.Float64("threshold", 10, "percent slowdown versus -baseline that counts as a regression")
+	flag.Parse()
+
+	bins, err := discover(*dir, *filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "runall: %v\n", err)
+		return 2
+	}
+	// selected holds the benchmarks -tags picked, nil without -tags
+	var selected map[string]bool
+	if *tagList != "" {
+		bins, selected = withTags(bins, strings.Split(*tagList, ","))
+	}
+	if len(bins) == 0 {
+		fmt.Fprintf(os.Stderr, "runall: no benchmarks matching %q (tags %q) in %s\n", *filter, *tagList, *dir)
+		return 2
+	}
+
+	var baseline []benchlib.Record
+	if *baselinePath != "" {
+		if baseline, err = loadBaseline(*baselinePath); err != nil {
+			fmt.Fprintf(os.Stderr, "runall: baseline: %v\n", err)
+			return 2
+		}
+	}
+
+	var sink *csvSink
+	if *csvPath != "" {
+		if sink, err = createCSV(*csvPath); err != nil {
+			fmt.Fprintf(os.Stderr, "runall: %v\n", err)
+			return 2
+		}
+		defer func() {
+			if err := sink.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "runall: %s: %v\n", *csvPath, err)
+			}
+		}()
+	}
+
+	var all []benchlib.Record
+	var failed []string
+	excluded := make(map[string]bool)
+	for _, bin := range bins {
+		recs, failedTests, err := run(bin, *timeout)
+		all = append(all, recs...)
+		if selected != nil {
+			// A binary's categories needn't match its name
+			for _, r := range recs {
+				selected[r.Category] = true
+			}
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", filepath.Base(bin), err))
+		}
+		for _, k := range failedTests {
+			excluded[k] = true
+		}
+		if errors.Is(err, errTimeout) {
+			// A killed benchmark's last numbers may be cut short
+			for _, r := range recs {
+				excluded[key(r)] = true
+			}
+		}
+		if sink != nil {
+			if err := sink.write(recs); err != nil {
+				fmt.Fprintf(os.Stderr, "runall: %s: %v\n", *csvPath, err)
+				return 2
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tTEST\tRESULT\tTIME (ms)\t")
+	for _, r := range all {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t\n", r.Category, r.Test, r.Result, r.TimeMs)
+	}
+	w.Flush()
+
+	fmt.Println()
+	byCategory, skipped := totals(all, excluded)
+	writeTotals(os.Stdout, byCategory, skipped)
+
+	if *jsonPath != "" {
+		if err := saveJSON(*jsonPath, all); err != nil {
+			fmt.Fprintf(os.Stderr, "runall: %v\n", err)
+			return 2
+		}
+	}
+
+	var cmps []comparison
+	var missing []string
+	if baseline != nil {
+		cmps, missing = compare(all, baseline, *threshold, *filter, selected)
+	}
+
+	if *mdPath != "" {
+		if err := saveMarkdown(*mdPath, all, cmps); err != nil {
+			fmt.Fprintf(os.Stderr, "runall: %v\n", err)
+			return 2
+		}
+	}
+
+	code := 0
+	if baseline != nil {
+		fmt.Println()
+		for _, c := range cmps {
+			if c.noise {
+				fmt.Printf("NOISE %s: %+.1f%%, but the baseline median is within this run's min-max range\n", c.key, c.pct)
+			}
+			if c.regressed {
+				fmt.Printf("REGRESSION %s: %dms -> %dms (%+.1f%%)\n", c.key, c.base.TimeMs, c.cur.TimeMs, c.pct)
+				code = 1
+			}
+		}
+		for _, m := range missing {
+			fmt.Printf("MISSING %s: in baseline but not in this run\n", m)
+			code = 1
+		}
+		if code == 0 {
+			fmt.Printf("No regressions beyond %.0f%% against %s\n", *threshold, *baselinePath)
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintln(os.Stderr)
+		for _, f := range failed {
+			fmt.Fprintf(os.Stderr, "FAILED %s\n", f)
+		}
+		return 1
+	}
+	return code
+}
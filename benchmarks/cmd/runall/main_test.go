@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func TestDiscoverSkipsTools(t *testing.T) {
+	dir := t.TempDir()
+	for name, mode := range map[string]os.FileMode{
+		"primes":    0o755,
+		"benchdiff": 0o755,
+		"compute":   0o755,
+		"notes.txt": 0o644,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := discover(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(dir, "primes")}; !slices.Equal(got, want) {
+		t.Errorf("discover = %v, want %v", got, want)
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds benchmark binaries")
+	}
+	dir := t.TempDir()
+	names := []string{"binarytrees", "io", "leibniz_pi", "nbody", "primes", "skynet", "sync", "wordcount"}
+	args := []string{"build", "-o", dir + string(filepath.Separator)}
+	for _, name := range names {
+		args = append(args, "../../"+name)
+	}
+	if out, err := exec.Command("go", args...).CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	bins, err := discover(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"concurrency"}, []string{"skynet", "sync"}},
+		{[]string{"gc"}, []string{"binarytrees", "skynet", "wordcount"}},
+		{[]string{"float", "io"}, []string{"io", "leibniz_pi", "nbody"}},
+	} {
+		kept, selected := withTags(bins, tc.tags)
+		var got []string
+		for _, bin := range kept {
+			got = append(got, strings.TrimSuffix(filepath.Base(bin), filepath.Ext(bin)))
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("withTags(%v) kept %v, want %v", tc.tags, got, tc.want)
+		}
+		for _, name := range tc.want {
+			if !selected[name] {
+				t.Errorf("withTags(%v) didn't select %s", tc.tags, name)
+			}
+		}
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	out := strings.Join([]string{
+		"BENCH:primes:count-1m:78498:12:12000000",
+		"ERROR:primes:count-10m: expected 664579, got 0",
+		"BENCH:meta:schema:3:0",
+		"not a bench line",
+	}, "\n")
+	recs, failedTests, err := parseOutput("primes", strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Test != "count-1m" || recs[1].Test != "schema" {
+		t.Errorf("recs = %+v, want primes:count-1m and meta:schema", recs)
+	}
+	if want := []string{"primes:count-10m"}; !slices.Equal(failedTests, want) {
+		t.Errorf("failedTests = %v, want %v", failedTests, want)
+	}
+}
+
+func TestParseOutputDropsNewerSchema(t *testing.T) {
+	// Output is sorted, so records can come before the schema header
+	out := strings.Join([]string{
+		"BENCH:primes:count-1m:78498:12:12000000",
+		"BENCH:meta:schema:4:0",
+		"BENCH:zeta:sum-1m:1:2:2000000",
+	}, "\n")
+	recs, _, err := parseOutput("primes", strings.NewReader(out))
+	if !errors.Is(err, benchlib.ErrSchema) {
+		t.Errorf("err = %v, want ErrSchema", err)
+	}
+	if len(recs) != 0 {
+		t.Errorf("recs = %+v, want none", recs)
+	}
+}
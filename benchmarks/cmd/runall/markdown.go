@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// writeMarkdown writes recs as a GitHub-flavored Markdown table for
+// pasting into a review. cmps, from compare, fill the vs-baseline column,
+// and regressed rows are set in bold. meta records describe the machine
+// rather than a test and are left out.
+func writeMarkdown(w io.Writer, recs []benchlib.Record, cmps []comparison) error {
+	byKey := make(map[string]comparison, len(cmps))
+	for _, c := range cmps {
+		byKey[c.key] = c
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "| Category | Test | Result | Time (ms) | vs baseline |")
+	fmt.Fprintln(bw, "|:---|:---|---:|---:|---:|")
+	for _, r := range recs {
+		if r.Category == "meta" {
+			continue
+		}
+		vs := ""
+		c, ok := byKey[key(r)]
+		if ok {
+			vs = fmt.Sprintf("%+.1f%%", c.pct)
+		}
+		cells := []string{r.Category, "`" + r.Test + "`", strconv.FormatInt(r.Result, 10), strconv.FormatInt(r.TimeMs, 10), vs}
+		if ok && c.regressed {
+			for i, cell := range cells {
+				cells[i] = "**" + cell + "**"
+			}
+		}
+		fmt.Fprintf(bw, "| %s | %s | %s | %s | %s |\n", cells[0], cells[1], cells[2], cells[3], cells[4])
+	}
+	return bw.Flush()
+}
+
+func saveMarkdown(path string, recs []benchlib.Record, cmps []comparison) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeMarkdown(f, recs, cmps); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	recs := []benchlib.Record{
+		{Category: "meta", Test: "env"},
+		{Category: "primes", Test: "count-10k", Result: 1229, TimeMs: 12},
+		{Category: "primes", Test: "sieve-100k", Result: 9592, TimeMs: 3},
+		{Category: "regex", Test: "match-1mb", Result: 24710, TimeMs: 40},
+	}
+	cmps := []comparison{
+		{key: "primes:count-10k", pct: 20, regressed: true},
+		{key: "primes:sieve-100k", pct: -4.25},
+	}
+
+	var b strings.Builder
+	if err := writeMarkdown(&b, recs, cmps); err != nil {
+		t.Fatal(err)
+	}
+	want := "| Category | Test | Result | Time (ms) | vs baseline |\n" +
+		"|:---|:---|---:|---:|---:|\n" +
+		"| **primes** | **`count-10k`** | **1229** | **12** | **+20.0%** |\n" +
+		"| primes | `sieve-100k` | 9592 | 3 | -4.2% |\n" +
+		"| regex | `match-1mb` | 24710 | 40 |  |\n"
+	if got := b.String(); got != want {
+		t.Errorf("writeMarkdown =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// killGroupOnCancel leaves exec's default of killing only the benchmark
+// process itself; process groups are a Unix concept.
+func killGroupOnCancel(cmd *exec.Cmd) {}
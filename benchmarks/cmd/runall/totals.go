@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// derivedSuffixes mark lines that restate the time of another line, such
+// as a -throughput rate or the -min and -max of a multi-run test whose
+// -med is counted instead. Summing them would count one test twice. A
+// -cold/-steady pair times the same work twice too, so only the -steady
+// mean is counted.
+var derivedSuffixes = []string{
+	"-throughput", "-per-op", "-min", "-max", "-cold",
+	"-p50", "-p90", "-p99", "-p99.9",
+}
+
+// groupSuffixes name the records of one test reported several ways, by
+// ReportRuns and ReportColdSteady. Checks on such a test fail under its
+// bare name.
+var groupSuffixes = []string{"-min", "-med", "-max", "-cold", "-steady"}
+
+// testGroup returns the key a record's test is checked under: its own, or
+// for one record of a group, the group's bare name.
+func testGroup(r benchlib.Record) string {
+	for _, suffix := range groupSuffixes {
+		if test, ok := strings.CutSuffix(r.Test, suffix); ok {
+			return r.Category + ":" + test
+		}
+	}
+	return key(r)
+}
+
+// errorKey returns the category:test key named by an
+// ERROR:<category>:<test>: <message> line.
+func errorKey(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "ERROR:")
+	if !ok {
+		return "", false
+	}
+	k, _, ok := strings.Cut(rest, ": ")
+	return k, ok
+}
+
+// totals sums time_ms per category over recs, skipping meta and derived
+// lines. Tests whose key, or whose group's bare key, is in excluded,
+// because they failed or their benchmark timed out, are left out of the
+// sums and returned as skipped.
+func totals(recs []benchlib.Record, excluded map[string]bool) (byCategory map[string]int64, skipped []string) {
+	byCategory = make(map[string]int64)
+	for _, r := range recs {
+		if r.Category == "meta" || slices.ContainsFunc(derivedSuffixes, func(s string) bool {
+			return strings.HasSuffix(r.Test, s)
+		}) {
+			continue
+		}
+		if excluded[key(r)] || excluded[testGroup(r)] {
+			skipped = append(skipped, key(r))
+			continue
+		}
+		byCategory[r.Category] += r.TimeMs
+	}
+	return byCategory, skipped
+}
+
+// writeTotals prints a BENCH:meta:total:<category>:<ms> line per category
+// and a BENCH:meta:total:all:<ms> grand total, followed by a note for each
+// test left out of them.
+func writeTotals(w io.Writer, byCategory map[string]int64, skipped []string) {
+	cats := make([]string, 0, len(byCategory))
+	for cat := range byCategory {
+		cats = append(cats, cat)
+	}
+	slices.Sort(cats)
+	var all int64
+	for _, cat := range cats {
+		fmt.Fprintf(w, "BENCH:meta:total:%s:%d\n", cat, byCategory[cat])
+		all += byCategory[cat]
+	}
+	fmt.Fprintf(w, "BENCH:meta:total:all:%d\n", all)
+	for _, k := range skipped {
+		fmt.Fprintf(w, "EXCLUDED %s: failed or timed out, not counted in the totals\n", k)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func TestErrorKey(t *testing.T) {
+	if k, ok := errorKey("ERROR:primes:count-10k: expected 1229, got 1228"); !ok || k != "primes:count-10k" {
+		t.Errorf("errorKey = %q, %v, want primes:count-10k", k, ok)
+	}
+	if _, ok := errorKey("BENCH:primes:count-10k:1229:1:1000"); ok {
+		t.Error("errorKey accepted a BENCH line")
+	}
+}
+
+func TestTotals(t *testing.T) {
+	recs := []benchlib.Record{
+		rec("meta", "seed", 0, 0),
+		rec("primes", "count-10k-min", 9, 0),
+		rec("primes", "count-10k-med", 10, 0),
+		rec("primes", "count-10k-max", 12, 0),
+		rec("primes", "sieve-100k", 4, 0),
+		rec("fanout", "throughput-100k", 30, 0),
+		rec("fanout", "throughput-100k-throughput", 30, 0),
+		rec("regex", "match-1mb", 50, 0),
+		rec("skynet", "spawn-100k-cold", 300, 0),
+		rec("skynet", "spawn-100k-steady", 100, 0),
+	}
+	byCategory, skipped := totals(recs, map[string]bool{"regex:match-1mb": true})
+
+	if len(byCategory) != 3 || byCategory["primes"] != 14 || byCategory["fanout"] != 30 || byCategory["skynet"] != 100 {
+		t.Errorf("totals = %v, want primes 14, fanout 30 and skynet 100 (steady only)", byCategory)
+	}
+	if len(skipped) != 1 || skipped[0] != "regex:match-1mb" {
+		t.Errorf("skipped = %v, want [regex:match-1mb]", skipped)
+	}
+
+	var b strings.Builder
+	writeTotals(&b, byCategory, skipped)
+	want := "BENCH:meta:total:fanout:30\n" +
+		"BENCH:meta:total:primes:14\n" +
+		"BENCH:meta:total:skynet:100\n" +
+		"BENCH:meta:total:all:144\n" +
+		"EXCLUDED regex:match-1mb: failed or timed out, not counted in the totals\n"
+	if b.String() != want {
+		t.Errorf("writeTotals =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestTotalsExcludesFailedRunGroup(t *testing.T) {
+	// ReportErr names a multi-run test by its bare name
+	recs := []benchlib.Record{
+		rec("primes", "count-100k-min", 9, 0),
+		rec("primes", "count-100k-med", 10, 0),
+		rec("primes", "count-100k-max", 12, 0),
+		rec("primes", "sieve-100k", 4, 0),
+	}
+	byCategory, skipped := totals(recs, map[string]bool{"primes:count-100k": true})
+	if byCategory["primes"] != 4 {
+		t.Errorf("primes total = %d, want 4 without the failed count-100k", byCategory["primes"])
+	}
+	if len(skipped) != 1 || skipped[0] != "primes:count-100k-med" {
+		t.Errorf("skipped = %v, want [primes:count-100k-med]", skipped)
+	}
+}
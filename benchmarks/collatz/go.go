@@ -0,0 +1,19 @@
+// Collatz Benchmark - Go implementation
+// Output format: BENCH:collatz:<test>:<result>:<time_ms>:<time_ns>
+//
+// The benchmark lives in internal/compute, registered as "collatz", so the
+// combined compute binary can run it too. This main runs it alone.
+package main
+
+import (
+	"os"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+	_ "github.com/navicore/patch-seq/benchmarks/internal/compute"
+)
+
+func main() {
+	defer benchlib.Finish()
+
+	benchlib.Run("collatz", os.Args[1:])
+}
@@ -1,34 +1,222 @@
 // Collections Benchmark - Go implementation
-// Output format: BENCH:collections:<test>:<result>:<time_ms>
+// Output format: BENCH:collections:<test>:<result>:<time_ms>:<time_ns>
+//
+// With BENCH_ALLOC=1 each test also reports <test>-bytes and <test>-mallocs.
+//
+// map-double-parallel splits its work across -parallelism goroutines
+// (GOMAXPROCS by default), independently of the -procs GOMAXPROCS
+// override, and is reported as
+// map-double-parallel-p<parallelism>-g<gomaxprocs>.
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
 	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
 )
 
 const numElements = 100000
 
+// appendElements is the number of values appended by the append-grow and
+// append-prealloc tests.
+const appendElements = 1000000
+
+// layoutElements is the number of records summed by aos-sum and soa-sum.
+const layoutElements = 1000000
+
+// searchElements is the length of the sorted slice binsearch-100k probes,
+// and searches the number of lookups it makes.
+const (
+	searchElements = 1000000
+	searches       = 100000
+)
+
+// record is one array-of-structs element for aos-sum; only a is summed,
+// so b and c just widen the stride.
+type record struct {
+	a, b, c int64
+}
+
+// rotateBy is the left rotation applied by rotate-100k.
+const rotateBy = 12345
+
+// quicksort sorts xs in place using Hoare partitioning around the middle
+// element.
+func quicksort(xs []int64) {
+	if len(xs) < 2 {
+		return
+	}
+	pivot := xs[len(xs)/2]
+	i, j := 0, len(xs)-1
+	for i <= j {
+		for xs[i] < pivot {
+			i++
+		}
+		for xs[j] > pivot {
+			j--
+		}
+		if i <= j {
+			xs[i], xs[j] = xs[j], xs[i]
+			i++
+			j--
+		}
+	}
+	quicksort(xs[:j+1])
+	quicksort(xs[i:])
+}
+
+// node is a singly-linked list cell, for pointer-chasing traversal.
+type node struct {
+	val  int64
+	next *node
+}
+
+// parallelDouble writes 2*src[i] into dst[i], splitting the work into
+// one chunk per worker, each doubled by its own goroutine.
+func parallelDouble(dst, src []int64, workers int) {
+	size := (len(src) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(src); lo += size {
+		hi := min(lo+size, len(src))
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				dst[i] = src[i] * 2
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// reverse reverses xs in place.
+func reverse(xs []int64) {
+	for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
+
+// rotate rotates xs left by k in place with three reversals.
+func rotate(xs []int64, k int) {
+	k %= len(xs)
+	reverse(xs[:k])
+	reverse(xs[k:])
+	reverse(xs)
+}
+
+// shuffle permutes xs in place with the Fisher-Yates algorithm, drawing
+// each swap index from the high bits of rng.
+func shuffle(xs []int64, rng *benchlib.LCG) {
+	for i := len(xs) - 1; i > 0; i-- {
+		j := int((rng.Next() >> 33) % uint64(i+1))
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
+
+// binarySearch returns the index of key in the ascending slice xs, and
+// whether it was found.
+func binarySearch(xs []int64, key int64) (int, bool) {
+	lo, hi := 0, len(xs)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if xs[mid] < key {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(xs) && xs[lo] == key
+}
+
+// weightedSum is a position-sensitive checksum: sum of i*xs[i].
+func weightedSum(xs []int64) int64 {
+	var sum int64
+	for i, v := range xs {
+		sum += int64(i) * v
+	}
+	return sum
+}
+
+// firstUnsorted returns the first index whose element is smaller than its
+// predecessor, or -1 if xs is sorted.
+func firstUnsorted(xs []int64) int {
+	for i := 1; i < len(xs); i++ {
+		if xs[i] < xs[i-1] {
+			return i
+		}
+	}
+	return -1
+}
+
 func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "gc")
+
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	parallelism := flag.Int("parallelism", 0, "goroutines for map-double-parallel (0 uses GOMAXPROCS)")
+	flag.Parse()
+	if *parallelism < 0 {
+		fmt.Fprintf(os.Stderr, "collections: -parallelism must not be negative, got %d\n", *parallelism)
+		os.Exit(2)
+	}
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	workers := *parallelism
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
 	// Build
+	meter := benchlib.StartAlloc()
 	start := time.Now()
 	data := make([]int64, numElements)
 	for i := int64(0); i < numElements; i++ {
 		data[i] = i
 	}
-	elapsed := time.Since(start).Milliseconds()
-	fmt.Printf("BENCH:collections:build-100k:%d:%d\n", len(data), elapsed)
+	elapsed := time.Since(start)
+	allocs := meter.Stop()
+	benchlib.Report("collections", "build-100k", int64(len(data)), elapsed)
+	benchlib.ReportAlloc("collections", "build-100k", allocs)
 
 	// Map (double each)
+	meter = benchlib.StartAlloc()
 	start = time.Now()
 	mapped := make([]int64, len(data))
 	for i, v := range data {
 		mapped[i] = v * 2
 	}
-	elapsed = time.Since(start).Milliseconds()
-	fmt.Printf("BENCH:collections:map-double:%d:%d\n", len(mapped), elapsed)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "map-double", int64(len(mapped)), elapsed)
+	benchlib.ReportAlloc("collections", "map-double", allocs)
+
+	// Parallel map (same doubling, one goroutine per -parallelism chunk)
+	parallelTest := benchlib.ParallelName("map-double-parallel", workers)
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	parMapped := make([]int64, len(data))
+	parallelDouble(parMapped, data, workers)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	var serialSum, parallelSum int64
+	for i := range mapped {
+		serialSum += mapped[i]
+		parallelSum += parMapped[i]
+	}
+	benchlib.Report("collections", parallelTest, parallelSum, elapsed)
+	benchlib.ReportAlloc("collections", parallelTest, allocs)
+	if parallelSum != serialSum {
+		benchlib.ReportErr("collections", parallelTest, serialSum, parallelSum)
+	}
 
 	// Filter (keep evens)
+	meter = benchlib.StartAlloc()
 	start = time.Now()
 	filtered := make([]int64, 0, len(data)/2)
 	for _, v := range data {
@@ -36,19 +224,25 @@ func main() {
 			filtered = append(filtered, v)
 		}
 	}
-	elapsed = time.Since(start).Milliseconds()
-	fmt.Printf("BENCH:collections:filter-evens:%d:%d\n", len(filtered), elapsed)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "filter-evens", int64(len(filtered)), elapsed)
+	benchlib.ReportAlloc("collections", "filter-evens", allocs)
 
 	// Fold (sum)
+	meter = benchlib.StartAlloc()
 	start = time.Now()
 	var total int64 = 0
 	for _, v := range data {
 		total += v
 	}
-	elapsed = time.Since(start).Milliseconds()
-	fmt.Printf("BENCH:collections:fold-sum:%d:%d\n", total, elapsed)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "fold-sum", total, elapsed)
+	benchlib.ReportAlloc("collections", "fold-sum", allocs)
 
 	// Chain (map -> filter -> fold)
+	meter = benchlib.StartAlloc()
 	start = time.Now()
 	var result int64 = 0
 	for _, v := range data {
@@ -57,6 +251,215 @@ func main() {
 			result += tripled
 		}
 	}
-	elapsed = time.Since(start).Milliseconds()
-	fmt.Printf("BENCH:collections:chain:%d:%d\n", result, elapsed)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "chain", result, elapsed)
+	benchlib.ReportAlloc("collections", "chain", allocs)
+
+	// Reverse in place (reversing again must restore the original)
+	work := slices.Clone(data)
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	reverse(work)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "reverse-100k", weightedSum(work), elapsed)
+	benchlib.ReportAlloc("collections", "reverse-100k", allocs)
+	if reverse(work); !slices.Equal(work, data) {
+		benchlib.Failf("collections", "reverse-100k", "reversing twice did not restore the original")
+	}
+
+	// Rotate left in place
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	rotate(work, rotateBy)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "rotate-100k", weightedSum(work), elapsed)
+	benchlib.ReportAlloc("collections", "rotate-100k", allocs)
+	for i, v := range work {
+		if want := data[(i+rotateBy)%len(data)]; v != want {
+			benchlib.Failf("collections", "rotate-100k", "index %d holds %d, want %d", i, v, want)
+			break
+		}
+	}
+
+	// Shuffle (seeded, so the resulting order is reproducible)
+	shuffled := slices.Clone(data)
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	shuffle(shuffled, benchlib.NewLCG(benchlib.Seed()))
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "shuffle-100k", weightedSum(shuffled), elapsed)
+	benchlib.ReportAlloc("collections", "shuffle-100k", allocs)
+	var shuffledSum int64
+	for _, v := range shuffled {
+		shuffledSum += v
+	}
+	if len(shuffled) != len(data) || shuffledSum != total {
+		benchlib.Failf("collections", "shuffle-100k", "got %d elements summing to %d, want %d summing to %d",
+			len(shuffled), shuffledSum, len(data), total)
+	}
+
+	// Sort (quicksort over pseudo-random values)
+	rng := benchlib.NewLCG(benchlib.Seed())
+	unsorted := make([]int64, numElements)
+	for i := range unsorted {
+		unsorted[i] = int64(rng.Next() >> 33)
+	}
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	quicksort(unsorted)
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	if i := firstUnsorted(unsorted); i >= 0 {
+		benchlib.Failf("collections", "sort-100k", "out of order at index %d", i)
+	} else {
+		benchlib.Report("collections", "sort-100k", int64(len(unsorted)), elapsed)
+		benchlib.ReportAlloc("collections", "sort-100k", allocs)
+	}
+
+	// Binary search (seeded keys into the even numbers, so about half hit)
+	sorted := make([]int64, searchElements)
+	for i := range sorted {
+		sorted[i] = int64(i) * 2
+	}
+	rng = benchlib.NewLCG(benchlib.Seed())
+	probes := make([]int64, searches)
+	for i := range probes {
+		probes[i] = int64((rng.Next() >> 33) % (2 * searchElements))
+	}
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	var indexSum int64
+	for _, key := range probes {
+		if i, ok := binarySearch(sorted, key); ok {
+			indexSum += int64(i)
+		}
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "binsearch-100k", indexSum, elapsed)
+	benchlib.ReportAlloc("collections", "binsearch-100k", allocs)
+	var wantSum int64
+	for _, key := range probes {
+		if key%2 == 0 {
+			wantSum += key / 2
+		}
+	}
+	if indexSum != wantSum {
+		benchlib.ReportErr("collections", "binsearch-100k", wantSum, indexSum)
+	}
+
+	// Map insert (deterministic keys spread across the int64 range)
+	keys := make([]int64, numElements)
+	for i := range keys {
+		keys[i] = int64(i) * 2654435761
+	}
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	table := make(map[int64]int64)
+	for i, k := range keys {
+		table[k] = int64(i)
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "map-insert-100k", int64(len(table)), elapsed)
+	benchlib.ReportAlloc("collections", "map-insert-100k", allocs)
+
+	// Map lookup (values are 0..n-1, so the sum must match fold-sum)
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	var found int64 = 0
+	for _, k := range keys {
+		found += table[k]
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "map-lookup-100k", found, elapsed)
+	benchlib.ReportAlloc("collections", "map-lookup-100k", allocs)
+	if found != total {
+		benchlib.ReportErr("collections", "map-lookup-100k", total, found)
+	}
+
+	// Linked list build and sum (same data, so the sum must match fold-sum)
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	var head *node
+	for _, v := range data {
+		head = &node{val: v, next: head}
+	}
+	var listSum int64 = 0
+	for n := head; n != nil; n = n.next {
+		listSum += n.val
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "list-sum-100k", listSum, elapsed)
+	benchlib.ReportAlloc("collections", "list-sum-100k", allocs)
+	if listSum != total {
+		benchlib.ReportErr("collections", "list-sum-100k", total, listSum)
+	}
+
+	// Append to a nil slice, letting append grow it, versus appending into
+	// a slice made with the final capacity up front
+	for _, tc := range []struct {
+		test string
+		init func() []int64
+	}{
+		{"append-grow-1m", func() []int64 { return nil }},
+		{"append-prealloc-1m", func() []int64 { return make([]int64, 0, appendElements) }},
+	} {
+		meter = benchlib.StartAlloc()
+		start = time.Now()
+		grown := tc.init()
+		for i := int64(0); i < appendElements; i++ {
+			grown = append(grown, i)
+		}
+		elapsed = time.Since(start)
+		allocs = meter.Stop()
+		benchlib.Report("collections", tc.test, int64(len(grown)), elapsed)
+		benchlib.ReportAlloc("collections", tc.test, allocs)
+		if len(grown) != appendElements {
+			benchlib.ReportErr("collections", tc.test, appendElements, int64(len(grown)))
+		}
+	}
+
+	// Sum one field of 1M records stored as an array of structs versus
+	// as parallel arrays (struct of arrays)
+	aos := make([]record, layoutElements)
+	soaA := make([]int64, layoutElements)
+	soaB := make([]int64, layoutElements)
+	soaC := make([]int64, layoutElements)
+	for i := range aos {
+		v := int64(i)
+		aos[i] = record{a: v, b: 2 * v, c: 3 * v}
+		soaA[i], soaB[i], soaC[i] = v, 2*v, 3*v
+	}
+
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	var aosSum int64 = 0
+	for i := range aos {
+		aosSum += aos[i].a
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "aos-sum", aosSum, elapsed)
+	benchlib.ReportAlloc("collections", "aos-sum", allocs)
+
+	meter = benchlib.StartAlloc()
+	start = time.Now()
+	var soaSum int64 = 0
+	for _, v := range soaA {
+		soaSum += v
+	}
+	elapsed = time.Since(start)
+	allocs = meter.Stop()
+	benchlib.Report("collections", "soa-sum", soaSum, elapsed)
+	benchlib.ReportAlloc("collections", "soa-sum", allocs)
+	if soaSum != aosSum {
+		benchlib.ReportErr("collections", "soa-sum", aosSum, soaSum)
+	}
 }
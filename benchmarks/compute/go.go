@@ -0,0 +1,27 @@
+// Compute Benchmarks - Go implementation
+//
+// One binary for the compute benchmarks in internal/compute. -list prints
+// them, -bench=<name> runs one, passing any arguments after -- to it, and
+// with no -bench they all run with their defaults:
+//
+//	go run ./compute -list
+//	go run ./compute -bench=fibonacci -- -n=30
+//
+// Each benchmark prints the same output as when it is built from its own
+// directory. A failing benchmark doesn't stop the ones after it; the
+// binary exits 1 once they have all run if any check failed, or 2 after a
+// bad argument.
+package main
+
+import (
+	"os"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+	_ "github.com/navicore/patch-seq/benchmarks/internal/compute"
+)
+
+func main() {
+	defer benchlib.Finish()
+
+	benchlib.Dispatch(os.Args[1:])
+}
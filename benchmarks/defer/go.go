@@ -0,0 +1,76 @@
+// Defer Benchmark - Go implementation
+// Output format: BENCH:defer:<test>:<result>:<time_ms>:<time_ns>
+//
+// Calls a small function -n times (100,000,000 by default) that runs its
+// exit step with a defer (with) and one that calls it directly before
+// returning (without). The result is the number of exit steps run, which
+// must be n, and a -per-op line reports the average time of one call in
+// picoseconds. Defer's cost has changed between Go releases, so compare
+// runs by the toolchain in the env header.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+type counter struct {
+	calls, exits int64
+}
+
+func (c *counter) exit() {
+	c.exits++
+}
+
+// withDefer and withoutDefer are kept out of line so every iteration is a
+// real call with its own frame for the defer to attach to.
+//
+//go:noinline
+func withDefer(c *counter) {
+	defer c.exit()
+	c.calls++
+}
+
+//go:noinline
+func withoutDefer(c *counter) {
+	c.calls++
+	c.exit()
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	n := flag.Int64("n", 100000000, "number of calls")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "defer: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	for _, tc := range []struct {
+		test string
+		call func(*counter)
+	}{
+		{"with", withDefer},
+		{"without", withoutDefer},
+	} {
+		var c counter
+		start := time.Now()
+		for i := int64(0); i < *n; i++ {
+			tc.call(&c)
+		}
+		elapsed := time.Since(start)
+
+		benchlib.Report("defer", tc.test, c.exits, elapsed)
+		benchlib.ReportPerOp("defer", tc.test, *n, elapsed)
+		if c.exits != *n {
+			benchlib.ReportErr("defer", tc.test, *n, c.exits)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Dispatch Benchmark - Go implementation
+// Output format: BENCH:dispatch:<test>:<result>:<time_ms>:<time_ns>
+//
+// Calls the same out-of-line method -n times (100,000,000 by default),
+// once through an interface value (interface) and once on the concrete
+// type (concrete), isolating the cost of dynamic dispatch. The result is
+// the accumulated sum, n(n-1)/2 for both, and a -per-op line reports the
+// average time of one call in picoseconds.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+type adder interface {
+	add(acc, v int64) int64
+}
+
+type plus struct{}
+
+// add is kept out of line so neither loop can inline it; the only
+// difference between them is how the call is resolved.
+//
+//go:noinline
+func (plus) add(acc, v int64) int64 {
+	return acc + v
+}
+
+// dynamic is a package variable rather than a local so the compiler can't
+// see its concrete type and devirtualize the interface calls.
+var dynamic adder = plus{}
+
+func sumInterface(a adder, n int64) int64 {
+	var acc int64
+	for i := int64(0); i < n; i++ {
+		acc = a.add(acc, i)
+	}
+	return acc
+}
+
+func sumConcrete(p plus, n int64) int64 {
+	var acc int64
+	for i := int64(0); i < n; i++ {
+		acc = p.add(acc, i)
+	}
+	return acc
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	n := flag.Int64("n", 100000000, "number of calls")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "dispatch: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	expected := *n * (*n - 1) / 2
+	for _, tc := range []struct {
+		test string
+		run  func() int64
+	}{
+		{"interface", func() int64 { return sumInterface(dynamic, *n) }},
+		{"concrete", func() int64 { return sumConcrete(plus{}, *n) }},
+	} {
+		start := time.Now()
+		sum := tc.run()
+		elapsed := time.Since(start)
+
+		benchlib.Report("dispatch", tc.test, sum, elapsed)
+		benchlib.ReportPerOp("dispatch", tc.test, *n, elapsed)
+		if sum != expected {
+			benchlib.ReportErr("dispatch", tc.test, expected, sum)
+		}
+	}
+}
@@ -1,59 +1,319 @@
 // Fanout Benchmark - Go implementation
-// Output format: BENCH:fanout:<test>:<result>:<time_ms>
+// Output format: BENCH:fanout:<test>:<result>:<time_ms>:<time_ns>
 //
 // 1 producer, N consumer workers.
-// Tests channel throughput with multiple receivers.
+// Tests channel throughput with multiple receivers. With -yield, workers
+// call runtime.Gosched after every message (mirroring chan.yield in the
+// Seq version) and the test is reported as throughput-100k-yield.
+// -buffer sets the work channel's capacity (0 for unbuffered hand-off);
+// sizes other than the default 100 add -buf<n> to the test name, keeping
+// the default name comparable with the other languages.
+//
+// With -duration the producer also runs for a fixed time rather than a
+// fixed count, stopped by a context, and the messages received in that
+// window are reported as rate-<duration>.
+//
+// fanout-pool-<n> instead spawns a goroutine per message, with at most
+// -concurrency of them in flight at once (bounded by a semaphore).
+//
+// select-mux-<k> reverses the topology: k producers each feed their own
+// channel and a single consumer selects across all of them.
+// select-after-<k> adds a timeout case fed by a fresh time.After on every
+// iteration, the timer-per-iteration pattern, and select-timer-<k> feeds
+// it from one time.Timer reset each time instead, so the two differ only
+// in timer creation and teardown.
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"runtime"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+	"github.com/navicore/patch-seq/benchmarks/internal/fanout"
 )
 
 const numMessages = 100000
 const numWorkers = 10
+const defaultBuffer = 100
+
+// pool processes numMessages with one goroutine per message, using a
+// buffered channel as a semaphore to cap in-flight goroutines at
+// concurrency. It returns the number of messages processed.
+func pool(concurrency int) int64 {
+	sem := make(chan struct{}, concurrency)
+	var processed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < numMessages; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			processed.Add(1)
+			<-sem
+		}()
+	}
+	wg.Wait()
+	return processed.Load()
+}
+
+// maxSources is the number of cases in selectMux's select statement.
+const maxSources = 8
+
+// startSources starts k producers that split numMessages between their own
+// channels, each closing its channel when done. Entries beyond k are nil.
+func startSources(k int) [maxSources]chan int64 {
+	var srcs [maxSources]chan int64
+	for j := 0; j < k; j++ {
+		srcs[j] = make(chan int64, 100)
+		go func(ch chan<- int64, first int64) {
+			for v := first; v < numMessages; v += int64(k) {
+				ch <- v
+			}
+			close(ch)
+		}(srcs[j], int64(j))
+	}
+	return srcs
+}
+
+// selectMux has k producers split numMessages between their own channels
+// while one consumer selects across them, and returns the sum of the
+// received values. Sources beyond k stay nil, which select never picks,
+// so a fixed select statement serves any k up to maxSources.
+func selectMux(k int) int64 {
+	srcs := startSources(k)
+
+	var sum int64
+	for open := k; open > 0; {
+		var v int64
+		var ok bool
+		var from int
+		select {
+		case v, ok = <-srcs[0]:
+			from = 0
+		case v, ok = <-srcs[1]:
+			from = 1
+		case v, ok = <-srcs[2]:
+			from = 2
+		case v, ok = <-srcs[3]:
+			from = 3
+		case v, ok = <-srcs[4]:
+			from = 4
+		case v, ok = <-srcs[5]:
+			from = 5
+		case v, ok = <-srcs[6]:
+			from = 6
+		case v, ok = <-srcs[7]:
+			from = 7
+		}
+		if !ok {
+			srcs[from] = nil
+			open--
+			continue
+		}
+		sum += v
+	}
+	return sum
+}
+
+// fanInTimeout is the timeout in selectTimeout's select. Producers never
+// stall that long, so it only adds the cost of arming a timer.
+const fanInTimeout = time.Second
+
+// selectTimeout is selectMux with a timeout case whose channel comes from
+// timeout, called once per iteration. It returns the sum of the received
+// values and how many times the timeout fired.
+func selectTimeout(k int, timeout func() <-chan time.Time) (sum, timeouts int64) {
+	srcs := startSources(k)
+
+	for open := k; open > 0; {
+		var v int64
+		var ok bool
+		var from int
+		select {
+		case v, ok = <-srcs[0]:
+			from = 0
+		case v, ok = <-srcs[1]:
+			from = 1
+		case v, ok = <-srcs[2]:
+			from = 2
+		case v, ok = <-srcs[3]:
+			from = 3
+		case v, ok = <-srcs[4]:
+			from = 4
+		case v, ok = <-srcs[5]:
+			from = 5
+		case v, ok = <-srcs[6]:
+			from = 6
+		case v, ok = <-srcs[7]:
+			from = 7
+		case <-timeout():
+			timeouts++
+			continue
+		}
+		if !ok {
+			srcs[from] = nil
+			open--
+			continue
+		}
+		sum += v
+	}
+	return sum, timeouts
+}
 
-func worker(workChan <-chan int, doneChan chan<- int) {
-	count := 0
-	for val := range workChan {
-		if val < 0 {
-			doneChan <- count
-			return
+// afterTimeout returns a new time.After channel on every call.
+func afterTimeout() <-chan time.Time {
+	return time.After(fanInTimeout)
+}
+
+// reusedTimeout returns a function that re-arms one timer on every call
+// and returns its channel. A fired but unreceived value is drained first
+// so a stale tick can't be mistaken for a new timeout.
+func reusedTimeout() (timeout func() <-chan time.Time, stop func()) {
+	t := time.NewTimer(fanInTimeout)
+	timeout = func() <-chan time.Time {
+		if !t.Stop() {
+			select {
+			case <-t.C:
+			default:
+			}
 		}
-		count++
-		runtime.Gosched()
+		t.Reset(fanInTimeout)
+		return t.C
 	}
+	return timeout, func() { t.Stop() }
 }
 
 func main() {
-	workChan := make(chan int, 100)
-	doneChan := make(chan int, numWorkers)
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
 
-	// Spawn workers
-	for i := 0; i < numWorkers; i++ {
-		go worker(workChan, doneChan)
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	yield := flag.Bool("yield", false, "yield to the scheduler after every received message")
+	concurrency := flag.Int("concurrency", numWorkers, "in-flight goroutine cap for fanout-pool-<n>")
+	sources := flag.Int("sources", maxSources, "producer channels for select-mux-<k> (1-8)")
+	buffer := flag.Int("buffer", defaultBuffer, "work channel capacity (0 for unbuffered)")
+	duration := flag.Duration("duration", 0, "also run rate-<duration>, sending for this long instead of a fixed count")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	flag.Parse()
+	if *concurrency < 1 {
+		fmt.Fprintf(os.Stderr, "fanout: -concurrency must be at least 1, got %d\n", *concurrency)
+		os.Exit(2)
+	}
+	if *sources < 1 || *sources > maxSources {
+		fmt.Fprintf(os.Stderr, "fanout: -sources must be between 1 and %d, got %d\n", maxSources, *sources)
+		os.Exit(2)
 	}
+	if *buffer < 0 {
+		fmt.Fprintf(os.Stderr, "fanout: -buffer must not be negative, got %d\n", *buffer)
+		os.Exit(2)
+	}
+	if *duration < 0 {
+		fmt.Fprintf(os.Stderr, "fanout: -duration must not be negative, got %v\n", *duration)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	p := fanout.NewPool(numWorkers, *buffer, *yield)
 
+	cpu := benchlib.StartCPU()
 	start := time.Now()
+	total := p.Run(numMessages)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
 
-	// Produce messages
-	for i := 0; i < numMessages; i++ {
-		workChan <- i
+	test := "throughput-100k"
+	if *buffer != defaultBuffer {
+		test += fmt.Sprintf("-buf%d", *buffer)
+	}
+	if *yield {
+		test += "-yield"
 	}
+	benchlib.ReportWithCPU("fanout", test, int64(total), elapsed, cpuTime)
+	benchlib.ReportThroughput("fanout", test, int64(total), elapsed)
+	if total != numMessages {
+		benchlib.ReportErr("fanout", test, numMessages, int64(total))
+	}
+
+	// Fixed time window instead of a fixed message count
+	if *duration > 0 {
+		p := fanout.NewPool(numWorkers, *buffer, *yield)
+		ctx, cancel := context.WithTimeout(context.Background(), *duration)
+
+		cpu := benchlib.StartCPU()
+		start := time.Now()
+		sent, received := p.RunFor(ctx)
+		elapsed := time.Since(start)
+		cpuTime := cpu.Stop()
+		cancel()
 
-	// Send sentinels
-	for i := 0; i < numWorkers; i++ {
-		workChan <- -1
+		test := "rate-" + duration.String()
+		benchlib.ReportWithCPU("fanout", test, int64(received), elapsed, cpuTime)
+		benchlib.ReportThroughput("fanout", test, int64(received), elapsed)
+		if received != sent {
+			benchlib.ReportErr("fanout", test, int64(sent), int64(received))
+		}
 	}
 
-	// Collect results
-	total := 0
-	for i := 0; i < numWorkers; i++ {
-		total += <-doneChan
+	// Semaphore-bounded pool
+	cpu = benchlib.StartCPU()
+	start = time.Now()
+	processed := pool(*concurrency)
+	elapsed = time.Since(start)
+	cpuTime = cpu.Stop()
+
+	test = fmt.Sprintf("fanout-pool-%d", *concurrency)
+	benchlib.ReportWithCPU("fanout", test, processed, elapsed, cpuTime)
+	if processed != numMessages {
+		benchlib.ReportErr("fanout", test, numMessages, processed)
 	}
 
-	elapsed := time.Since(start).Milliseconds()
+	// Select multiplexer
+	cpu = benchlib.StartCPU()
+	start = time.Now()
+	sum := selectMux(*sources)
+	elapsed = time.Since(start)
+	cpuTime = cpu.Stop()
 
-	fmt.Printf("BENCH:fanout:throughput-100k:%d:%d\n", total, elapsed)
+	test = fmt.Sprintf("select-mux-%d", *sources)
+	benchlib.ReportWithCPU("fanout", test, sum, elapsed, cpuTime)
+	expected := int64(numMessages) * (numMessages - 1) / 2
+	if sum != expected {
+		benchlib.ReportErr("fanout", test, expected, sum)
+	}
+
+	// Select with a timeout case: a timer per iteration versus one reused
+	reused, stopTimer := reusedTimeout()
+	for _, tc := range []struct {
+		name    string
+		timeout func() <-chan time.Time
+	}{
+		{"select-after", afterTimeout},
+		{"select-timer", reused},
+	} {
+		cpu = benchlib.StartCPU()
+		start = time.Now()
+		sum, timeouts := selectTimeout(*sources, tc.timeout)
+		elapsed = time.Since(start)
+		cpuTime = cpu.Stop()
+
+		test = fmt.Sprintf("%s-%d", tc.name, *sources)
+		benchlib.ReportWithCPU("fanout", test, sum, elapsed, cpuTime)
+		if sum != expected {
+			benchlib.ReportErr("fanout", test, expected, sum)
+		}
+		if timeouts > 0 {
+			fmt.Fprintf(os.Stderr, "fanout: %s: %d select timeouts fired\n", test, timeouts)
+		}
+	}
+	stopTimer()
+	leaks.Verify("fanout")
 }
@@ -0,0 +1,72 @@
+// Fanout Sweep - Go implementation
+// Output format: BENCH:fanout:sweep-w<nnn>[-buf<n>][-yield]:<result>:<time_ms>:<time_ns>:<cv_pct>:<cpu_ms>
+//
+// Runs the fanout throughput test (one producer, numMessages through a
+// shared channel) once for each worker count in 1, 2, 4, ..., 128, giving
+// one BENCH line per setting so throughput can be plotted against
+// concurrency from a single run. The result is the number of messages
+// the workers received, which must be numMessages at every setting. As in
+// fanout, a non-default -buffer appends -buf<n> to each test name and
+// -yield appends -yield, so differently configured sweeps don't share
+// keys.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+	"github.com/navicore/patch-seq/benchmarks/internal/fanout"
+)
+
+const numMessages = 100000
+const defaultBuffer = 100
+
+// workerCounts are the settings swept, doubling from one worker.
+var workerCounts = []int{1, 2, 4, 8, 16, 32, 64, 128}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	yield := flag.Bool("yield", false, "yield to the scheduler after every received message")
+	buffer := flag.Int("buffer", defaultBuffer, "work channel capacity (0 for unbuffered)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	flag.Parse()
+	if *buffer < 0 {
+		fmt.Fprintf(os.Stderr, "fanout_sweep: -buffer must not be negative, got %d\n", *buffer)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	for _, workers := range workerCounts {
+		p := fanout.NewPool(workers, *buffer, *yield)
+
+		cpu := benchlib.StartCPU()
+		start := time.Now()
+		total := p.Run(numMessages)
+		elapsed := time.Since(start)
+		cpuTime := cpu.Stop()
+
+		// zero-padded so the sorted output lists settings in order
+		test := fmt.Sprintf("sweep-w%03d", workers)
+		if *buffer != defaultBuffer {
+			test += fmt.Sprintf("-buf%d", *buffer)
+		}
+		if *yield {
+			test += "-yield"
+		}
+		benchlib.ReportWithCPU("fanout", test, int64(total), elapsed, cpuTime)
+		if total != numMessages {
+			benchlib.ReportErr("fanout", test, numMessages, int64(total))
+		}
+	}
+	leaks.Verify("fanout")
+}
@@ -0,0 +1,80 @@
+// Generics Benchmark - Go implementation
+// Output format: BENCH:generics:<test>:<result>:<time_ms>:<time_ns>
+//
+// Sums a 100,000-element []int64 (values 0..n-1) with a generic Sum[T]
+// and with a hand-written int64 version. Each pass is too quick to time
+// alone, so every sample repeats the sum until it has run for -min-time
+// and reports the per-sum time; BENCH_RUNS samples give -min, -med and
+// -max lines with a trailing <cv_pct> field. Both results must equal
+// n(n-1)/2, and generic-diff reports the median generic time minus the
+// median specialized time in nanoseconds.
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const numElements = 100000
+
+// integer is the integer-type constraint from golang.org/x/exp/constraints,
+// declared here so the benchmarks keep to the standard library.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+func Sum[T integer](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func sumInt64(xs []int64) int64 {
+	var total int64
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	minTime := flag.Duration("min-time", benchlib.DefaultMinTime, "repeat each sum until a sample takes at least this long")
+	flag.Parse()
+	benchlib.ReportEnv()
+
+	data := make([]int64, numElements)
+	for i := range data {
+		data[i] = int64(i)
+	}
+	expected := int64(numElements) * (numElements - 1) / 2
+	runs := benchlib.Runs()
+
+	var medians [2]time.Duration
+	for i, tc := range []struct {
+		test string
+		sum  func([]int64) int64
+	}{
+		{"generic-sum", Sum[int64]},
+		{"mono-sum", sumInt64},
+	} {
+		var result int64
+		samples := benchlib.RunMinTime(runs, *minTime, func() { result = tc.sum(data) })
+		benchlib.ReportRuns("generics", tc.test, result, samples)
+		if result != expected {
+			benchlib.ReportErr("generics", tc.test, expected, result)
+		}
+		medians[i] = benchlib.Median(samples)
+	}
+
+	// the difference can be negative, so it goes in the result field only
+	diff := medians[0] - medians[1]
+	benchlib.Report("generics", "generic-diff", diff.Nanoseconds(), 0)
+}
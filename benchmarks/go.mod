@@ -0,0 +1,3 @@
+module github.com/navicore/patch-seq/benchmarks
+
+go 1.22
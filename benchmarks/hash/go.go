@@ -0,0 +1,70 @@
+// Hash Benchmark - Go implementation
+// Output format: BENCH:hash:<test>:<result>:<time_ms>:<time_ns>
+//
+// Computes SHA-256 over a 16MB buffer -repeat times (10 by default). The
+// buffer is the shared LCG output (seed from BENCH_SEED, default 1), each
+// value stored as 8 little-endian bytes, so other runtimes can regenerate
+// it exactly. The result is the number of bytes hashed, and a -throughput
+// line reports bytes per second. With the default seed the digest must
+// match the known value.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	bufferSize = 16 << 20
+	// expectedDigest is the SHA-256 of the default-seed buffer.
+	expectedDigest = "d9241c80b6c64f36c8ee38b49830fbb0f362b196f5961d41f27f75a530d96ad9"
+)
+
+func buffer(n int, seed uint64) []byte {
+	rng := benchlib.NewLCG(seed)
+	buf := make([]byte, n)
+	for i := 0; i+8 <= n; i += 8 {
+		binary.LittleEndian.PutUint64(buf[i:], rng.Next())
+	}
+	return buf
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	repeat := flag.Int("repeat", 10, "number of times to hash the buffer")
+	flag.Parse()
+	if *repeat < 1 {
+		fmt.Fprintf(os.Stderr, "hash: -repeat must be at least 1, got %d\n", *repeat)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	buf := buffer(bufferSize, benchlib.Seed())
+
+	var digest [sha256.Size]byte
+	start := time.Now()
+	for i := 0; i < *repeat; i++ {
+		digest = sha256.Sum256(buf)
+	}
+	elapsed := time.Since(start)
+
+	test := fmt.Sprintf("sha256-16mb-x%d", *repeat)
+	total := int64(bufferSize) * int64(*repeat)
+	got := hex.EncodeToString(digest[:])
+	benchlib.Notef("digest: %s", got)
+	benchlib.Report("hash", test, total, elapsed)
+	benchlib.ReportRate("hash", test, total, "bytes", elapsed)
+	if benchlib.Seed() == benchlib.DefaultSeed && got != expectedDigest {
+		benchlib.Failf("hash", test, "expected digest %s, got %s", expectedDigest, got)
+		benchlib.Exit(1)
+	}
+}
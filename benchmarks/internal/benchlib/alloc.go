@@ -0,0 +1,59 @@
+package benchlib
+
+import (
+	"os"
+	"runtime"
+)
+
+// allocOutput is set when BENCH_ALLOC=1, enabling the -bytes and -mallocs
+// lines that ReportAlloc prints.
+var allocOutput = os.Getenv("BENCH_ALLOC") == "1"
+
+// AllocStats is the heap activity of a benchmark body.
+type AllocStats struct {
+	Bytes   uint64
+	Mallocs uint64
+}
+
+// AllocMeter records heap activity between StartAlloc and Stop. A nil
+// meter, returned when allocation reporting is off, measures nothing.
+type AllocMeter struct {
+	before runtime.MemStats
+}
+
+// StartAlloc forces a collection for a clean baseline and snapshots the
+// allocator counters. Call it before starting the timer, since both the
+// GC and ReadMemStats stop the world.
+func StartAlloc() *AllocMeter {
+	if !allocOutput {
+		return nil
+	}
+	m := &AllocMeter{}
+	runtime.GC()
+	runtime.ReadMemStats(&m.before)
+	return m
+}
+
+// Stop returns the bytes and allocations made since StartAlloc. Call it
+// after the timer stops and before printing, which allocates.
+func (m *AllocMeter) Stop() AllocStats {
+	if m == nil {
+		return AllocStats{}
+	}
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	return AllocStats{
+		Bytes:   after.TotalAlloc - m.before.TotalAlloc,
+		Mallocs: after.Mallocs - m.before.Mallocs,
+	}
+}
+
+// ReportAlloc prints <test>-bytes and <test>-mallocs lines carrying the
+// counts in the result field. It prints nothing unless BENCH_ALLOC=1.
+func ReportAlloc(category, test string, a AllocStats) {
+	if !allocOutput {
+		return
+	}
+	Report(category, test+"-bytes", int64(a.Bytes), 0)
+	Report(category, test+"-mallocs", int64(a.Mallocs), 0)
+}
@@ -0,0 +1,28 @@
+package benchlib
+
+import "math"
+
+// FNV-1a 64-bit parameters, used by FloatChecksum.
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// FloatChecksum combines the IEEE 754 bit patterns of vals into one
+// value, so two runtimes agree only when every float is bit-identical:
+// 0.1+0.2 and 0.3 differ, as do -0 and +0, and NaNs compare by payload.
+// It is FNV-1a applied to whole 64-bit words:
+//
+//	h = 14695981039346656037
+//	for each v: h = (h xor bits(v)) * 1099511628211 (mod 2^64)
+//
+// where bits(v) is the float's raw bit pattern as an unsigned 64-bit
+// integer. An empty slice checksums to the offset basis.
+func FloatChecksum(vals []float64) uint64 {
+	h := uint64(fnvOffset)
+	for _, v := range vals {
+		h ^= math.Float64bits(v)
+		h *= fnvPrime
+	}
+	return h
+}
@@ -0,0 +1,33 @@
+package benchlib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloatChecksum(t *testing.T) {
+	vals := []float64{0, 1, math.Copysign(0, -1), 0.1, math.Pi, -2.5e-300}
+	// pinned so other runtimes can check their port against it
+	if got, want := FloatChecksum(vals), uint64(0x28f442baf40e2d76); got != want {
+		t.Errorf("FloatChecksum(%v) = %#x, want %#x", vals, got, want)
+	}
+	if got := FloatChecksum(nil); got != fnvOffset {
+		t.Errorf("FloatChecksum(nil) = %#x, want the offset basis", got)
+	}
+}
+
+func TestFloatChecksumBitExact(t *testing.T) {
+	a, b := 0.1, 0.2
+	for _, tc := range []struct {
+		name string
+		x, y float64
+	}{
+		{"rounding", a + b, 0.3},
+		{"signed zero", 0, math.Copysign(0, -1)},
+		{"nan payload", math.NaN(), math.Float64frombits(0x7ff8000000000002)},
+	} {
+		if FloatChecksum([]float64{tc.x}) == FloatChecksum([]float64{tc.y}) {
+			t.Errorf("%s: %v and %v have the same checksum", tc.name, tc.x, tc.y)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package benchlib
+
+import "time"
+
+// CPUMeter measures the CPU time the whole process (user plus system,
+// across all threads) spends between StartCPU and Stop. Comparing it with
+// wall time shows how much parallel work a concurrent benchmark did.
+type CPUMeter struct {
+	start time.Duration
+}
+
+// StartCPU snapshots the process CPU time.
+func StartCPU() *CPUMeter {
+	return &CPUMeter{start: processCPUTime()}
+}
+
+// Stop returns the CPU time used since StartCPU, or -1 on platforms that
+// can't measure it.
+func (m *CPUMeter) Stop() time.Duration {
+	now := processCPUTime()
+	if now < 0 || m.start < 0 {
+		return -1
+	}
+	return now - m.start
+}
+
+// ReportWithCPU is Report with a trailing cpu_ms field holding cpu in
+// milliseconds, or -1 when cpu is negative (unmeasurable).
+func ReportWithCPU(category, test string, result int64, elapsed, cpu time.Duration) {
+	rec := newRecord(category, test, result, elapsed)
+	cpuMs := int64(-1)
+	if cpu >= 0 {
+		cpuMs = cpu.Milliseconds()
+	}
+	rec.CPUMs = &cpuMs
+	report(rec)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package benchlib
+
+import "time"
+
+// processCPUTime is unsupported outside Unix.
+func processCPUTime() time.Duration {
+	return -1
+}
@@ -0,0 +1,31 @@
+package benchlib
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCPUMeter(t *testing.T) {
+	m := StartCPU()
+	deadline := time.Now().Add(20 * time.Millisecond)
+	for time.Now().Before(deadline) {
+	}
+	cpu := m.Stop()
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		if cpu != -1 {
+			t.Errorf("Stop() = %v on %s, want -1", cpu, runtime.GOOS)
+		}
+		return
+	}
+	if cpu <= 0 {
+		t.Errorf("Stop() after busy-waiting = %v, want a positive CPU time", cpu)
+	}
+}
+
+func TestReportWithCPUUnmeasured(t *testing.T) {
+	line := capture(t, func() { ReportWithCPU("sync", "mutex-counter", 1000000, time.Millisecond, -1) })
+	if want := "BENCH:sync:mutex-counter:1000000:1:1000000::-1\n"; line != want {
+		t.Errorf("ReportWithCPU printed %q, want %q", line, want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package benchlib
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user plus system time of every thread in the
+// process, or -1 if getrusage fails.
+func processCPUTime() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return -1
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
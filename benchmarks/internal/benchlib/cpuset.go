@@ -0,0 +1,65 @@
+package benchlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// errAffinityUnsupported is returned by pinCPUs on platforms without
+// sched_setaffinity.
+var errAffinityUnsupported = errors.New("CPU pinning is only supported on Linux")
+
+// ParseCPUSet parses a CPU list such as "0,1" or "0-3,6" into sorted,
+// de-duplicated CPU numbers.
+func ParseCPUSet(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid CPU %q in cpuset %q", lo, s)
+		}
+		last := first
+		if isRange {
+			last, err = strconv.Atoi(hi)
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU range %q in cpuset %q", part, s)
+			}
+		}
+		for c := first; c <= last; c++ {
+			seen[c] = true
+		}
+	}
+	cpus := make([]int, 0, len(seen))
+	for c := range seen {
+		cpus = append(cpus, c)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// SetupCPUSet pins the process to the CPUs in a -cpuset flag value, when
+// non-empty, to keep scheduler migrations out of latency measurements.
+// Call it at the start of main, before any goroutines are started. An
+// invalid cpuset exits with status 2; on platforms without CPU affinity
+// it warns and carries on unpinned.
+func SetupCPUSet(spec string) {
+	if spec == "" {
+		return
+	}
+	cpus, err := ParseCPUSet(spec)
+	if err == nil {
+		err = pinCPUs(cpus)
+	}
+	switch {
+	case errors.Is(err, errAffinityUnsupported):
+		fmt.Fprintf(os.Stderr, "warning: %v; ignoring -cpuset\n", err)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "-cpuset: %v\n", err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package benchlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// maxCPUs is the kernel's default CPU_SETSIZE.
+const maxCPUs = 1024
+
+// pinCPUs sets the affinity of every thread in the process. Affinity is
+// per thread on Linux and the Go runtime already has several, so each task
+// in /proc/self/task is pinned; threads created later inherit the mask.
+func pinCPUs(cpus []int) error {
+	var mask [maxCPUs / 64]uint64
+	for _, c := range cpus {
+		if c >= maxCPUs {
+			return fmt.Errorf("CPU %d is out of range (max %d)", c, maxCPUs-1)
+		}
+		mask[c/64] |= 1 << (c % 64)
+	}
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+			uintptr(tid), unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+		// A thread that exited since the directory was read is fine
+		if errno != 0 && !errors.Is(errno, syscall.ESRCH) {
+			return fmt.Errorf("sched_setaffinity for thread %d: %w", tid, errno)
+		}
+	}
+	return nil
+}
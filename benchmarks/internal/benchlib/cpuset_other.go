@@ -0,0 +1,8 @@
+//go:build !linux
+
+package benchlib
+
+// pinCPUs is unsupported outside Linux.
+func pinCPUs(cpus []int) error {
+	return errAffinityUnsupported
+}
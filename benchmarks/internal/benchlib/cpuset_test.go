@@ -0,0 +1,31 @@
+package benchlib
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCPUSet(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want []int
+	}{
+		{"0", []int{0}},
+		{"0,1", []int{0, 1}},
+		{"4-6,0", []int{0, 4, 5, 6}},
+		{"2,1-3", []int{1, 2, 3}},
+	} {
+		got, err := ParseCPUSet(tt.in)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseCPUSet(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCPUSetInvalid(t *testing.T) {
+	for _, in := range []string{"", "a", "-1", "1,", "3-1", "1-x", "0,,1"} {
+		if got, err := ParseCPUSet(in); err == nil {
+			t.Errorf("ParseCPUSet(%q) = %v, want an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package benchlib
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// subBucketBits sets the histogram's precision: each power-of-two range is
+// split into 1<<subBucketBits linear sub-buckets, so a recorded value is
+// known to within about 1.6%.
+const (
+	subBucketBits  = 6
+	subBucketCount = 1 << subBucketBits
+	numBuckets     = subBucketCount + (64-subBucketBits)*subBucketCount
+)
+
+// Histogram is an HDR-style log-linear histogram of durations. Recording is
+// a constant-time array increment, cheap enough for per-message timing.
+// The zero value is ready to use.
+type Histogram struct {
+	counts [numBuckets]uint64
+	total  uint64
+}
+
+// bucketOf maps a value in nanoseconds to its bucket index. Values below
+// subBucketCount get exact buckets.
+func bucketOf(v uint64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+	exp := bits.Len64(v) - 1
+	shift := exp - subBucketBits
+	sub := int(v>>shift) - subBucketCount
+	return subBucketCount + (exp-subBucketBits)*subBucketCount + sub
+}
+
+// bucketMax returns the largest value that maps to bucket i.
+func bucketMax(i int) uint64 {
+	if i < subBucketCount {
+		return uint64(i)
+	}
+	shift := (i - subBucketCount) / subBucketCount
+	sub := uint64((i-subBucketCount)%subBucketCount + subBucketCount)
+	return (sub+1)<<shift - 1
+}
+
+// Record adds one duration. Negative durations count as zero.
+func (h *Histogram) Record(d time.Duration) {
+	h.counts[bucketOf(uint64(max(d, 0)))]++
+	h.total++
+}
+
+// Count returns the number of recorded durations.
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Quantile returns the q quantile (0 < q <= 1) as the upper bound of the
+// bucket holding it, so it never understates a latency. It returns 0 for
+// an empty histogram.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.total)))
+	rank = min(max(rank, 1), h.total)
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return time.Duration(min(bucketMax(i), math.MaxInt64))
+		}
+	}
+	return 0
+}
+
+// latencyQuantiles are the percentiles ReportLatency prints, with the
+// suffix each is reported under.
+var latencyQuantiles = []struct {
+	suffix string
+	q      float64
+}{
+	{"-p50", 0.50},
+	{"-p90", 0.90},
+	{"-p99", 0.99},
+	{"-p99.9", 0.999},
+}
+
+// ReportLatency prints the p50, p90, p99 and p99.9 of h as BENCH lines
+// named <test>-p50 and so on. The result field is the latency in
+// nanoseconds, and the time fields carry the same value.
+func ReportLatency(category, test string, h *Histogram) {
+	for _, lq := range latencyQuantiles {
+		d := h.Quantile(lq.q)
+		Report(category, test+lq.suffix, d.Nanoseconds(), d)
+	}
+}
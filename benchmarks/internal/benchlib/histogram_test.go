@@ -0,0 +1,75 @@
+package benchlib
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBucketBounds(t *testing.T) {
+	for _, v := range []uint64{0, 1, 63, 64, 65, 127, 128, 1000, 123456789, 1 << 40, 1<<63 - 1, 1<<64 - 1} {
+		i := bucketOf(v)
+		if i < 0 || i >= numBuckets {
+			t.Fatalf("bucketOf(%d) = %d, out of range", v, i)
+		}
+		hi := bucketMax(i)
+		if v > hi {
+			t.Errorf("bucketOf(%d) = %d, whose max %d is below the value", v, i, hi)
+		}
+		if i > 0 && v <= bucketMax(i-1) {
+			t.Errorf("bucketOf(%d) = %d, but bucket %d already covers it", v, i, i-1)
+		}
+		// Precision: the bucket is no wider than 1/subBucketCount of the value
+		if v >= subBucketCount && hi-v > v/subBucketCount {
+			t.Errorf("bucket for %d spans up to %d, too coarse", v, hi)
+		}
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	var h Histogram
+	if h.Quantile(0.5) != 0 {
+		t.Errorf("empty histogram p50 = %v, want 0", h.Quantile(0.5))
+	}
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	if h.Count() != 1000 {
+		t.Fatalf("Count() = %d, want 1000", h.Count())
+	}
+	for _, tt := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 500 * time.Microsecond},
+		{0.9, 900 * time.Microsecond},
+		{0.99, 990 * time.Microsecond},
+		{1, 1000 * time.Microsecond},
+	} {
+		got := h.Quantile(tt.q)
+		// Quantiles report bucket upper bounds, so allow the bucket width
+		if got < tt.want || got > tt.want+tt.want/subBucketCount {
+			t.Errorf("Quantile(%v) = %v, want about %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestReportLatency(t *testing.T) {
+	var h Histogram
+	h.Record(40 * time.Nanosecond)
+	output := capture(t, func() { ReportLatency("pingpong", "roundtrip-100k", &h) })
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	want := []string{"roundtrip-100k-p50", "roundtrip-100k-p90", "roundtrip-100k-p99", "roundtrip-100k-p99.9"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), output)
+	}
+	for i, line := range lines {
+		rec, err := ParseLine(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Test != want[i] || rec.Result != 40 || rec.TimeNs != 40 {
+			t.Errorf("ParseLine(%q) = %+v", line, rec)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package benchlib
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// LCG is the pseudo-random generator shared by every benchmark that needs
+// reproducible input data. It is the 64-bit linear congruential generator
+// with Knuth's MMIX constants:
+//
+//	state = state*6364136223846793005 + 1442695040888963407 (mod 2^64)
+//
+// Next returns the new state. Implementations in other languages must use
+// the same constants and wrapping arithmetic to produce identical data.
+//
+// The low bits of a power-of-two LCG have short periods; callers wanting
+// smaller numbers should shift the high bits down rather than take a
+// remainder of the full value.
+type LCG struct {
+	state uint64
+}
+
+const (
+	lcgMultiplier = 6364136223846793005
+	lcgIncrement  = 1442695040888963407
+)
+
+// NewLCG returns a generator starting from seed.
+func NewLCG(seed uint64) *LCG {
+	return &LCG{state: seed}
+}
+
+// Next advances the generator and returns the new state.
+func (g *LCG) Next() uint64 {
+	g.state = g.state*lcgMultiplier + lcgIncrement
+	return g.state
+}
+
+// DefaultSeed is the seed used when BENCH_SEED is unset. Expected results
+// that depend on generated data are only known for this seed.
+const DefaultSeed = 1
+
+var (
+	seedOnce sync.Once
+	seed     uint64
+)
+
+// Seed returns the LCG seed for randomized inputs, taken from BENCH_SEED
+// and defaulting to DefaultSeed. The first call also reports it as a
+// BENCH:meta:seed:<seed> header so a run can be reproduced from its log.
+func Seed() uint64 {
+	seedOnce.Do(func() {
+		seed = DefaultSeed
+		if v := os.Getenv("BENCH_SEED"); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "benchlib: invalid BENCH_SEED %q, using %d\n", v, DefaultSeed)
+			} else {
+				seed = n
+			}
+		}
+		// The result field is signed; seeds past MaxInt64 show as negative
+		Report("meta", "seed", int64(seed), 0)
+	})
+	return seed
+}
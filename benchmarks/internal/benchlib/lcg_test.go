@@ -0,0 +1,58 @@
+package benchlib
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLCGSequence(t *testing.T) {
+	// Other-language implementations must reproduce exactly these values.
+	want := []uint64{
+		7806831264735756412,
+		9396908728118811419,
+		11960119808228829710,
+		7062582979898595269,
+		14673421054488193520,
+		9232803539723513983,
+		10218303843513747618,
+		1206773305466921929,
+		15490212636682683044,
+		3660572683296592931,
+	}
+	g := NewLCG(1)
+	for i, w := range want {
+		if got := g.Next(); got != w {
+			t.Fatalf("output %d = %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestSeedDefault(t *testing.T) {
+	t.Setenv("BENCH_SEED", "")
+	seedOnce = sync.Once{}
+	t.Cleanup(func() { seedOnce = sync.Once{} })
+	var got uint64
+	output := capture(t, func() { got = Seed() })
+	if got != DefaultSeed || output != "BENCH:meta:seed:1:0:0\n" {
+		t.Errorf("Seed() = %d with output %q, want %d and a meta line", got, output, DefaultSeed)
+	}
+}
+
+func TestSeedFromEnv(t *testing.T) {
+	t.Setenv("BENCH_SEED", "42")
+	seedOnce = sync.Once{}
+	t.Cleanup(func() { seedOnce = sync.Once{} })
+	output := capture(t, func() {
+		if got := Seed(); got != 42 {
+			t.Errorf("Seed() = %d, want 42", got)
+		}
+		// Later calls return the same seed without reporting again
+		if got := Seed(); got != 42 {
+			t.Errorf("second Seed() = %d, want 42", got)
+		}
+	})
+	if n := strings.Count(output, "BENCH:meta:seed:"); n != 1 {
+		t.Errorf("seed reported %d times, want once: %q", n, output)
+	}
+}
@@ -0,0 +1,48 @@
+package benchlib
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// leakGrace is how long Verify waits for finished goroutines to exit.
+// A goroutine that has sent its last value may not have returned yet.
+var leakGrace = time.Second
+
+// LeakCheck detects goroutines left running by a benchmark. A nil check,
+// returned when checking is off, does nothing.
+type LeakCheck struct {
+	before int
+}
+
+// StartLeakCheck snapshots the goroutine count when enabled, typically by
+// a -leakcheck flag. Call it before the benchmark spawns anything.
+func StartLeakCheck(enabled bool) *LeakCheck {
+	if !enabled {
+		return nil
+	}
+	return &LeakCheck{before: runtime.NumGoroutine()}
+}
+
+// Verify waits up to leakGrace for the goroutine count to fall back to
+// the snapshot. If it doesn't, Verify prints an ERROR:<category>:leakcheck
+// line with the number of extra goroutines, dumps their stacks to stderr
+// and returns false. Like any failed check, that fails the run at Finish.
+func (c *LeakCheck) Verify(category string) bool {
+	if c == nil {
+		return true
+	}
+	deadline := time.Now().Add(leakGrace)
+	for runtime.NumGoroutine() > c.before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	leaked := runtime.NumGoroutine() - c.before
+	if leaked <= 0 {
+		return true
+	}
+	Failf(category, "leakcheck", "%d goroutines still running after the benchmark", leaked)
+	pprof.Lookup("goroutine").WriteTo(os.Stderr, 1)
+	return false
+}
@@ -0,0 +1,34 @@
+package benchlib
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeakCheckNil(t *testing.T) {
+	if c := StartLeakCheck(false); c != nil || !c.Verify("sync") {
+		t.Errorf("disabled leak check = %v, want a nil check that passes", c)
+	}
+}
+
+func TestLeakCheck(t *testing.T) {
+	saved := leakGrace
+	leakGrace = 50 * time.Millisecond
+	t.Cleanup(func() { leakGrace = saved })
+
+	c := StartLeakCheck(true)
+	done := make(chan struct{})
+	go func() { <-done }()
+	var ok bool
+	output := capture(t, func() { ok = c.Verify("fanout") })
+	if ok || !strings.HasPrefix(output, "ERROR:fanout:leakcheck: 1 goroutines") {
+		t.Errorf("Verify with a blocked goroutine = %v, %q; want a leak error", ok, output)
+	}
+
+	close(done)
+	output = capture(t, func() { ok = c.Verify("fanout") })
+	if !ok || output != "" {
+		t.Errorf("Verify after the goroutine exits = %v, %q; want a pass", ok, output)
+	}
+}
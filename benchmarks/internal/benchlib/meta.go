@@ -0,0 +1,82 @@
+package benchlib
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SetupProcs applies a -procs override, when positive, and prints the
+// effective GOMAXPROCS as a BENCH:meta:gomaxprocs:<n> header so results
+// from different machines can be compared. Call it once at the start of
+// main, before anything is timed.
+func SetupProcs(procs int) {
+	if procs > 0 {
+		runtime.GOMAXPROCS(procs)
+	}
+	Report("meta", "gomaxprocs", int64(runtime.GOMAXPROCS(0)), 0)
+}
+
+// Env describes the machine and toolchain a benchmark ran on.
+type Env struct {
+	GoVersion  string `json:"go_version"`
+	GOOS       string `json:"goos"`
+	GOARCH     string `json:"goarch"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+}
+
+// CurrentEnv returns the Env of this process.
+func CurrentEnv() Env {
+	return Env{
+		// Development builds put a timestamp (with colons) in the version
+		GoVersion:  strings.NewReplacer(":", "_", "/", "_").Replace(runtime.Version()),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+	}
+}
+
+// ReportEnv prints the environment header
+// BENCH:meta:env:<goversion>/<goos>/<goarch>/<ncpu>/<gomaxprocs>,
+// along with the BENCH:meta:schema:<version> record naming the line format
+// (see SchemaVersion). Call it once at the start of main, after any
+// GOMAXPROCS override.
+func ReportEnv() {
+	Report("meta", "schema", SchemaVersion, 0)
+	env := CurrentEnv()
+	if jsonOutput {
+		printJSON(struct {
+			Category string `json:"category"`
+			Test     string `json:"test"`
+			Env
+		}{"meta", "env", env})
+		return
+	}
+	fmt.Fprintf(out, "BENCH:meta:env:%s/%s/%s/%d/%d\n",
+		env.GoVersion, env.GOOS, env.GOARCH, env.NumCPU, env.GOMAXPROCS)
+}
+
+// ParseEnv parses a line printed by ReportEnv. It returns ErrNotBench for
+// any other line, including ordinary BENCH lines.
+func ParseEnv(s string) (Env, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "BENCH:meta:env:")
+	if !ok {
+		return Env{}, ErrNotBench
+	}
+	fields := strings.Split(rest, "/")
+	if len(fields) != 5 {
+		return Env{}, fmt.Errorf("malformed env line %q: want 5 fields, got %d", s, len(fields))
+	}
+	ncpu, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return Env{}, fmt.Errorf("malformed env line %q: ncpu %q is not an integer", s, fields[3])
+	}
+	procs, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return Env{}, fmt.Errorf("malformed env line %q: gomaxprocs %q is not an integer", s, fields[4])
+	}
+	return Env{fields[0], fields[1], fields[2], ncpu, procs}, nil
+}
@@ -0,0 +1,38 @@
+package benchlib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvRoundTrip(t *testing.T) {
+	output := capture(t, ReportEnv)
+	schema, line, _ := strings.Cut(output, "\n")
+	if want := fmt.Sprintf("BENCH:meta:schema:%d:0:0", SchemaVersion); schema != want {
+		t.Errorf("ReportEnv schema header = %q, want %q", schema, want)
+	}
+	env, err := ParseEnv(line)
+	if err != nil {
+		t.Fatalf("ParseEnv(%q): %v", line, err)
+	}
+	if want := CurrentEnv(); env != want {
+		t.Errorf("ParseEnv(%q) = %+v, want %+v", line, env, want)
+	}
+}
+
+func TestParseEnvRejects(t *testing.T) {
+	if _, err := ParseEnv("BENCH:primes:count-10k:1229:3"); !errors.Is(err, ErrNotBench) {
+		t.Errorf("ParseEnv of a record line: error = %v, want ErrNotBench", err)
+	}
+	for _, line := range []string{
+		"BENCH:meta:env:go1.22/linux/amd64/8",
+		"BENCH:meta:env:go1.22/linux/amd64/eight/8",
+	} {
+		_, err := ParseEnv(line)
+		if err == nil || errors.Is(err, ErrNotBench) {
+			t.Errorf("ParseEnv(%q) error = %v, want a malformed-line error", line, err)
+		}
+	}
+}
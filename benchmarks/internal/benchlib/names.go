@@ -0,0 +1,29 @@
+package benchlib
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// SizeName formats a problem size the way test names spell it:
+// 10000 becomes "10k", 1000000 becomes "1m", and sizes that aren't a
+// whole multiple of a thousand are printed in full.
+func SizeName(n int64) string {
+	switch {
+	case n != 0 && n%1000000 == 0:
+		return strconv.FormatInt(n/1000000, 10) + "m"
+	case n != 0 && n%1000 == 0:
+		return strconv.FormatInt(n/1000, 10) + "k"
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+// ParallelName appends the worker goroutine count and the current
+// GOMAXPROCS to test, as <test>-p<parallelism>-g<gomaxprocs>, so results
+// say both how many goroutines shared the work and how many could run at
+// once. Call it after SetupProcs.
+func ParallelName(test string, parallelism int) string {
+	return fmt.Sprintf("%s-p%d-g%d", test, parallelism, runtime.GOMAXPROCS(0))
+}
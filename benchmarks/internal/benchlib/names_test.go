@@ -0,0 +1,31 @@
+package benchlib
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSizeName(t *testing.T) {
+	tests := map[int64]string{
+		0:       "0",
+		20:      "20",
+		1500:    "1500",
+		10000:   "10k",
+		100000:  "100k",
+		500000:  "500k",
+		1000000: "1m",
+		2500000: "2500k",
+	}
+	for n, want := range tests {
+		if got := SizeName(n); got != want {
+			t.Errorf("SizeName(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestParallelName(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(3))
+	if got, want := ParallelName("cas-counter", 10), "cas-counter-p10-g3"; got != want {
+		t.Errorf("ParallelName = %q, want %q", got, want)
+	}
+}
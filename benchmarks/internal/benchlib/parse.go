@@ -0,0 +1,92 @@
+package benchlib
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotBench is returned by ParseLine for lines that aren't BENCH lines at
+// all, such as ERROR lines or human-readable output. Callers usually skip
+// these rather than treat them as failures.
+var ErrNotBench = errors.New("not a BENCH line")
+
+// SchemaVersion is the version of the BENCH line format, announced by
+// ReportEnv as BENCH:meta:schema:<version>. Bump it whenever a field is
+// added or its meaning changes:
+//
+//  1. category, test, result, time_ms
+//  2. adds time_ns and cv_pct
+//  3. adds cpu_ms
+const SchemaVersion = 3
+
+// ErrSchema is returned by ParseLine for a schema header announcing a
+// version this parser doesn't know. Lines that follow it may carry fields
+// ParseLine would misread, so callers shouldn't trust them.
+var ErrSchema = errors.New("unsupported schema version")
+
+// ParseLine parses a line printed by Report:
+//
+//	BENCH:<category>:<test>:<result>:<time_ms>[:<time_ns>[:<cv_pct>[:<cpu_ms>]]]
+//
+// cv_pct may be empty when cpu_ms follows it.
+//
+// Category and test names never contain colons, so fields are positional.
+// The result and time fields must be integers, which is what catches a
+// line whose name did contain a colon. Fields after the known ones are
+// ignored so older parsers survive format additions, and the optional
+// fields are simply absent in output from older schema versions. A schema
+// header newer than SchemaVersion is rejected with ErrSchema.
+func ParseLine(s string) (Record, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if fields[0] != "BENCH" {
+		return Record{}, ErrNotBench
+	}
+	if len(fields) < 5 {
+		return Record{}, fmt.Errorf("malformed BENCH line %q: want at least 5 fields, got %d", s, len(fields))
+	}
+	if fields[1] == "" || fields[2] == "" {
+		return Record{}, fmt.Errorf("malformed BENCH line %q: empty category or test", s)
+	}
+
+	rec := Record{Category: fields[1], Test: fields[2]}
+	var err error
+	if rec.Result, err = parseField(s, "result", fields[3]); err != nil {
+		return Record{}, err
+	}
+	if rec.TimeMs, err = parseField(s, "time_ms", fields[4]); err != nil {
+		return Record{}, err
+	}
+	if len(fields) > 5 {
+		if rec.TimeNs, err = parseField(s, "time_ns", fields[5]); err != nil {
+			return Record{}, err
+		}
+	}
+	if len(fields) > 6 && (fields[6] != "" || len(fields) == 7) {
+		cv, err := strconv.ParseFloat(fields[6], 64)
+		if err != nil {
+			return Record{}, fmt.Errorf("malformed BENCH line %q: cv_pct %q is not a number", s, fields[6])
+		}
+		rec.CVPct = &cv
+	}
+	if len(fields) > 7 {
+		cpu, err := parseField(s, "cpu_ms", fields[7])
+		if err != nil {
+			return Record{}, err
+		}
+		rec.CPUMs = &cpu
+	}
+	if rec.Category == "meta" && rec.Test == "schema" && (rec.Result < 1 || rec.Result > SchemaVersion) {
+		return Record{}, fmt.Errorf("%w %d in %q: this parser reads versions 1 to %d", ErrSchema, rec.Result, s, SchemaVersion)
+	}
+	return rec, nil
+}
+
+func parseField(line, name, field string) (int64, error) {
+	v, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed BENCH line %q: %s %q is not an integer", line, name, field)
+	}
+	return v, nil
+}
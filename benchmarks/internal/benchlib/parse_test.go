@@ -0,0 +1,154 @@
+package benchlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// capture redirects report output into a buffer for the duration of f,
+// streaming records so they appear in the order they were reported.
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	savedOut, savedStream := out, streamOutput
+	out, streamOutput = &buf, true
+	t.Cleanup(func() { out, streamOutput = savedOut, savedStream })
+	f()
+	return buf.String()
+}
+
+func TestParseLineRoundTrip(t *testing.T) {
+	line := capture(t, func() {
+		Report("primes", "count-10k", 1229, 3*time.Millisecond+41230)
+	})
+	rec, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine(%q): %v", line, err)
+	}
+	want := Record{Category: "primes", Test: "count-10k", Result: 1229, TimeMs: 3, TimeNs: 3041230}
+	if rec != want {
+		t.Errorf("ParseLine(%q) = %+v, want %+v", line, rec, want)
+	}
+}
+
+func TestParseLineRoundTripRuns(t *testing.T) {
+	output := capture(t, func() {
+		ReportRuns("fibonacci", "fib-naive-30", 832040, []time.Duration{100, 200, 300})
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %q", len(lines), output)
+	}
+	for i, suffix := range []string{"-min", "-med", "-max"} {
+		rec, err := ParseLine(lines[i])
+		if err != nil {
+			t.Fatalf("ParseLine(%q): %v", lines[i], err)
+		}
+		if rec.Test != "fib-naive-30"+suffix || rec.Result != 832040 || rec.TimeNs != int64(100*(i+1)) {
+			t.Errorf("ParseLine(%q) = %+v", lines[i], rec)
+		}
+		if rec.CVPct == nil || *rec.CVPct != 50 {
+			t.Errorf("ParseLine(%q) cv_pct = %v, want 50", lines[i], rec.CVPct)
+		}
+	}
+}
+
+func TestParseLineRoundTripCPU(t *testing.T) {
+	line := capture(t, func() {
+		ReportWithCPU("skynet", "spawn-100k", 4999950000, 170*time.Millisecond, 650*time.Millisecond)
+	})
+	if want := "BENCH:skynet:spawn-100k:4999950000:170:170000000::650\n"; line != want {
+		t.Fatalf("ReportWithCPU printed %q, want %q", line, want)
+	}
+	rec, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine(%q): %v", line, err)
+	}
+	if rec.CVPct != nil || rec.CPUMs == nil || *rec.CPUMs != 650 {
+		t.Errorf("ParseLine(%q) = %+v, want cpu_ms 650 and no cv_pct", line, rec)
+	}
+}
+
+func TestParseLineCVAndCPU(t *testing.T) {
+	rec, err := ParseLine("BENCH:fanout:throughput-100k:100000:250:250000000:1.50:-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.CVPct == nil || *rec.CVPct != 1.5 || rec.CPUMs == nil || *rec.CPUMs != -1 {
+		t.Errorf("ParseLine = %+v, want cv_pct 1.5 and cpu_ms -1", rec)
+	}
+}
+
+func TestParseLineLegacyFiveFields(t *testing.T) {
+	rec, err := ParseLine("BENCH:fibonacci:fib-fast-30:832040:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.TimeMs != 0 || rec.TimeNs != 0 || rec.CVPct != nil {
+		t.Errorf("unexpected optional fields: %+v", rec)
+	}
+}
+
+func TestParseLineIgnoresUnknownTrailingFields(t *testing.T) {
+	rec, err := ParseLine("BENCH:skynet:spawn-100k:4999950000:120:120000000:1.50:999:future")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Result != 4999950000 || rec.TimeMs != 120 {
+		t.Errorf("ParseLine = %+v", rec)
+	}
+}
+
+func TestParseLineSchema(t *testing.T) {
+	for v := int64(1); v <= SchemaVersion; v++ {
+		line := fmt.Sprintf("BENCH:meta:schema:%d:0:0", v)
+		if rec, err := ParseLine(line); err != nil || rec.Result != v {
+			t.Errorf("ParseLine(%q) = %+v, %v; want version %d", line, rec, err, v)
+		}
+	}
+	for _, line := range []string{
+		fmt.Sprintf("BENCH:meta:schema:%d:0:0", SchemaVersion+1),
+		"BENCH:meta:schema:0:0:0",
+	} {
+		if _, err := ParseLine(line); !errors.Is(err, ErrSchema) {
+			t.Errorf("ParseLine(%q) error = %v, want ErrSchema", line, err)
+		}
+	}
+}
+
+func TestParseLineNotBench(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"ERROR:primes:count-10k: expected 1229, got 1",
+		"Throughput: 100 msg/sec",
+		`{"category":"primes"}`,
+	} {
+		if _, err := ParseLine(line); !errors.Is(err, ErrNotBench) {
+			t.Errorf("ParseLine(%q) error = %v, want ErrNotBench", line, err)
+		}
+	}
+}
+
+func TestParseLineMalformed(t *testing.T) {
+	for _, line := range []string{
+		"BENCH:primes:count-10k:1229",
+		"BENCH::count-10k:1229:3",
+		"BENCH:primes:count-10k:many:3",
+		"BENCH:primes:count-10k:1229:3ms",
+		// A colon inside the test name shifts the numeric fields
+		"BENCH:primes:count:10k:1229:3",
+		"BENCH:primes:count-10k:1229:3:fast",
+		"BENCH:primes:count-10k:1229:3:3000000:noisy",
+		"BENCH:skynet:spawn-100k:1:3:3000000::busy",
+		"BENCH:primes:count-10k:1229:3:3000000:",
+	} {
+		_, err := ParseLine(line)
+		if err == nil || errors.Is(err, ErrNotBench) {
+			t.Errorf("ParseLine(%q) error = %v, want a malformed-line error", line, err)
+		}
+	}
+}
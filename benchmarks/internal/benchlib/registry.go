@@ -0,0 +1,131 @@
+package benchlib
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// benchmark is one registered benchmark.
+type benchmark struct {
+	run  func(args []string)
+	tags []string
+}
+
+// registry maps benchmark names to their registrations.
+var registry = map[string]benchmark{}
+
+// Register makes a benchmark available to Run and Dispatch. run receives
+// the benchmark's own command-line arguments and parses them with its own
+// flag.FlagSet. tags say what the benchmark stresses, such as "cpu",
+// "float", "gc", "io" or "concurrency", so drivers can select by them.
+// The name should match the benchmark's directory, which is what its
+// standalone binary is called. Benchmarks register from init, so a
+// duplicate name is a programming error and panics.
+func Register(name string, run func(args []string), tags ...string) {
+	if _, dup := registry[name]; dup {
+		panic("benchlib: benchmark " + name + " registered twice")
+	}
+	registry[name] = benchmark{run: run, tags: tags}
+}
+
+// Tags returns the tags name was registered with, or nil if no benchmark
+// has that name.
+func Tags(name string) []string {
+	return registry[name].tags
+}
+
+// HasTag reports whether name was registered with any of tags.
+func HasTag(name string, tags []string) bool {
+	for _, t := range Tags(name) {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Registered returns the names of all registered benchmarks, sorted.
+func Registered() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Run runs the named benchmark with args, exiting with status 2 if no
+// benchmark has that name. Under BENCH_LIST_TAGS=1 it prints the
+// benchmark's tags and exits instead; see Declare.
+func Run(name string, args []string) {
+	b, ok := registry[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown benchmark %q (available: %v)\n", name, Registered())
+		os.Exit(2)
+	}
+	if tagQuery(b.tags) {
+		os.Exit(0)
+	}
+	b.run(args)
+}
+
+// listTags is set when BENCH_LIST_TAGS=1. A benchmark binary then prints
+// its tags on one comma-separated line and exits without running, which
+// is how runall -tags learns the tags of binaries it can't link.
+var listTags = os.Getenv("BENCH_LIST_TAGS") == "1"
+
+// Declare gives the tags of a standalone main that isn't in the registry,
+// with the same meaning as Register's. Such mains call it right after
+// deferring Finish, before parsing flags; it returns at once unless
+// BENCH_LIST_TAGS=1, when it prints the tags and exits.
+func Declare(tags ...string) {
+	if tagQuery(tags) {
+		os.Exit(0)
+	}
+}
+
+// tagQuery prints tags and reports true under BENCH_LIST_TAGS=1.
+func tagQuery(tags []string) bool {
+	if !listTags {
+		return false
+	}
+	fmt.Fprintln(out, strings.Join(tags, ","))
+	return true
+}
+
+// Dispatch is the body of a multi-benchmark main. It accepts -list, which
+// prints the registered benchmarks, and -bench=<name>, which runs that
+// benchmark with the remaining arguments:
+//
+//	compute -bench=fibonacci -- -n=30
+//
+// Without -bench every registered benchmark runs in name order with its
+// defaults.
+func Dispatch(args []string) {
+	fs := flag.NewFlagSet("dispatch", flag.ExitOnError)
+	list := fs.Bool("list", false, "print the available benchmarks and exit")
+	bench := fs.String("bench", "", "benchmark to run")
+	fs.Parse(args)
+	if *list {
+		printList(os.Stdout)
+		return
+	}
+	if *bench == "" {
+		for _, name := range Registered() {
+			Run(name, nil)
+		}
+		return
+	}
+	Run(*bench, fs.Args())
+}
+
+// printList prints each benchmark name and its tags.
+func printList(w io.Writer) {
+	for _, name := range Registered() {
+		fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(Tags(name), ","))
+	}
+}
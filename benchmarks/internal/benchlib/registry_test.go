@@ -0,0 +1,61 @@
+package benchlib
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	saved := registry
+	registry = map[string]benchmark{}
+	t.Cleanup(func() { registry = saved })
+
+	var got []string
+	Register("zeta", func(args []string) { got = append(got, "zeta:"+strings.Join(args, ",")) }, "cpu", "gc")
+	Register("alpha", func(args []string) { got = append(got, "alpha:"+strings.Join(args, ",")) })
+
+	if names := Registered(); !slices.Equal(names, []string{"alpha", "zeta"}) {
+		t.Fatalf("Registered() = %v, want [alpha zeta]", names)
+	}
+
+	if tags := Tags("zeta"); !slices.Equal(tags, []string{"cpu", "gc"}) {
+		t.Errorf(`Tags("zeta") = %v, want [cpu gc]`, tags)
+	}
+	if !HasTag("zeta", []string{"io", "gc"}) || HasTag("alpha", []string{"cpu"}) || HasTag("missing", []string{"cpu"}) {
+		t.Error("HasTag matched the wrong benchmarks")
+	}
+
+	Dispatch([]string{"-bench=zeta", "--", "-n=3"})
+	Dispatch(nil)
+	want := []string{"zeta:-n=3", "alpha:", "zeta:"}
+	if !slices.Equal(got, want) {
+		t.Errorf("runs = %q, want %q", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate Register did not panic")
+		}
+	}()
+	Register("alpha", func([]string) {})
+}
+
+func TestTagQuery(t *testing.T) {
+	var buf bytes.Buffer
+	saved := out
+	out = &buf
+	t.Cleanup(func() { out = saved; listTags = false })
+
+	if tagQuery([]string{"concurrency", "gc"}) {
+		t.Fatal("tagQuery answered without BENCH_LIST_TAGS=1")
+	}
+	listTags = true
+	if !tagQuery([]string{"concurrency", "gc"}) {
+		t.Fatal("tagQuery didn't answer under BENCH_LIST_TAGS=1")
+	}
+	if got, want := buf.String(), "concurrency,gc\n"; got != want {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+}
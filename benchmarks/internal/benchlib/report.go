@@ -0,0 +1,252 @@
+// Package benchlib holds the output helpers shared by the Go benchmarks.
+//
+// Every benchmark reports its results through this package so the
+// BENCH line format lives in exactly one place.
+package benchlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Record is a single benchmark result.
+type Record struct {
+	Category string `json:"category"`
+	Test     string `json:"test"`
+	Result   int64  `json:"result"`
+	TimeMs   int64  `json:"time_ms"`
+	TimeNs   int64  `json:"time_ns"`
+	// CVPct is the run-to-run coefficient of variation, present only for
+	// multi-run results.
+	CVPct *float64 `json:"cv_pct,omitempty"`
+	// CPUMs is the process CPU time in milliseconds, -1 if it couldn't be
+	// measured, present only for results reported with ReportWithCPU.
+	CPUMs *int64 `json:"cpu_ms,omitempty"`
+	// Env is the environment the record was produced in. Benchmarks don't
+	// set it; collectors such as runall attach it from the env header.
+	Env *Env `json:"env,omitempty"`
+}
+
+func newRecord(category, test string, result int64, elapsed time.Duration) Record {
+	return Record{
+		Category: category,
+		Test:     test,
+		Result:   result,
+		TimeMs:   elapsed.Milliseconds(),
+		TimeNs:   elapsed.Nanoseconds(),
+	}
+}
+
+func (r Record) withCV(cv float64) Record {
+	r.CVPct = &cv
+	return r
+}
+
+// jsonOutput is set when BENCH_FORMAT=json, switching every report from
+// the colon-delimited line to one JSON object per line.
+var jsonOutput = os.Getenv("BENCH_FORMAT") == "json"
+
+// out is where reports are written; tests swap it for a buffer.
+var out io.Writer = os.Stdout
+
+// streamOutput is set when BENCH_STREAM=1, printing each record as it is
+// reported instead of holding it for Flush. Handy when debugging a
+// benchmark that hangs before it exits.
+var streamOutput = os.Getenv("BENCH_STREAM") == "1"
+
+// pending holds reported records until Flush.
+var (
+	pendingMu sync.Mutex
+	pending   []Record
+)
+
+// Report prints the canonical result line:
+// BENCH:<category>:<test>:<result>:<time_ms>:<time_ns>
+//
+// time_ns was appended after time_ms so that parsers reading only the
+// first five fields keep working; it carries the precision that time_ms
+// loses for sub-millisecond runs.
+//
+// Multi-run results append a further <cv_pct> field; see ReportRuns.
+// Results with CPU time append <cv_pct>:<cpu_ms>, leaving cv_pct empty
+// when there is none; see ReportWithCPU.
+func Report(category, test string, result int64, elapsed time.Duration) {
+	report(newRecord(category, test, result, elapsed))
+}
+
+// report queues rec for Flush, or prints it at once when streaming.
+func report(rec Record) {
+	if streamOutput {
+		emit(rec)
+		return
+	}
+	pendingMu.Lock()
+	pending = append(pending, rec)
+	pendingMu.Unlock()
+}
+
+// Flush prints the records reported so far, sorted by category and then
+// test, so output doesn't depend on the order in which goroutines finished
+// reporting. Mains normally get it through Finish.
+func Flush() {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Category != pending[j].Category {
+			return pending[i].Category < pending[j].Category
+		}
+		return pending[i].Test < pending[j].Test
+	})
+	for _, rec := range pending {
+		emit(rec)
+	}
+	pending = nil
+}
+
+// Exit flushes pending records and exits with code. Use it instead of
+// os.Exit once anything has been reported; os.Exit skips deferred calls.
+func Exit(code int) {
+	Flush()
+	os.Exit(code)
+}
+
+// failed is set by every verification failure; see Failed.
+var failed atomic.Bool
+
+// Failed reports whether ReportErr or Failf has been called.
+func Failed() bool {
+	return failed.Load()
+}
+
+// usageErr is set by Usagef; see Finish.
+var usageErr atomic.Bool
+
+// Usagef prints a command-line error for the named benchmark and makes
+// Finish exit with status 2. Registered benchmarks call it and return
+// instead of exiting, so the combined compute binary carries on with the
+// others.
+func Usagef(name, format string, args ...any) {
+	fmt.Fprintf(os.Stderr, name+": "+format+"\n", args...)
+	usageErr.Store(true)
+}
+
+// Finish flushes pending records and exits with status 1 if any check
+// failed, so a wrong result fails the process even when main carries on
+// to its remaining tests, or with status 2 after a Usagef. Every main
+// defers it first thing.
+func Finish() {
+	Flush()
+	switch {
+	case usageErr.Load():
+		os.Exit(2)
+	case Failed():
+		os.Exit(1)
+	}
+}
+
+func emit(rec Record) {
+	if jsonOutput {
+		printJSON(rec)
+		return
+	}
+	line := fmt.Sprintf("BENCH:%s:%s:%d:%d:%d", rec.Category, rec.Test, rec.Result, rec.TimeMs, rec.TimeNs)
+	if rec.CVPct != nil {
+		line += fmt.Sprintf(":%.2f", *rec.CVPct)
+	} else if rec.CPUMs != nil {
+		// cv_pct is positional, so leave it empty to reach cpu_ms
+		line += ":"
+	}
+	if rec.CPUMs != nil {
+		line += fmt.Sprintf(":%d", *rec.CPUMs)
+	}
+	fmt.Fprintln(out, line)
+}
+
+// ReportThroughput prints a <test>-throughput line whose result field is
+// messages per second. See ReportRate.
+func ReportThroughput(category, test string, messages int64, elapsed time.Duration) {
+	ReportRate(category, test, messages, "msg", elapsed)
+}
+
+// ReportRate prints a <test>-throughput line whose result field is count
+// units per second, computed from nanoseconds so sub-millisecond runs
+// don't divide by zero. Outside JSON mode it also prints a human-readable
+// summary. An elapsed time of zero can't give a rate, so the rate is
+// reported as 0 and the summary says so.
+func ReportRate(category, test string, count int64, unit string, elapsed time.Duration) {
+	var perSec int64
+	if elapsed > 0 {
+		perSec = int64(float64(count) / elapsed.Seconds())
+	}
+	if !jsonOutput {
+		if elapsed > 0 {
+			fmt.Fprintf(out, "Throughput: %d %s/sec\n", perSec, unit)
+		} else {
+			fmt.Fprintf(out, "Throughput: %d %s in under 1ns\n", count, unit)
+		}
+	}
+	Report(category, test+"-throughput", perSec, elapsed)
+}
+
+// ReportPerOp prints a <test>-per-op line whose result field is the
+// average time of one of ops operations in picoseconds, so calls that
+// take a nanosecond or two keep their fractional part. Outside JSON mode
+// it also prints a human-readable summary in nanoseconds.
+func ReportPerOp(category, test string, ops int64, elapsed time.Duration) {
+	var ps int64
+	if ops > 0 {
+		ps = int64(float64(elapsed.Nanoseconds()) * 1000 / float64(ops))
+	}
+	if !jsonOutput {
+		fmt.Fprintf(out, "Per op: %.3f ns\n", float64(ps)/1000)
+	}
+	Report(category, test+"-per-op", ps, elapsed)
+}
+
+// Notef prints a human-readable line alongside the results, such as a
+// computed value worth eyeballing. Like the summaries of ReportRate and
+// ReportPerOp it is left out in JSON mode, where every line on stdout
+// must be a JSON object.
+func Notef(format string, args ...any) {
+	if !jsonOutput {
+		fmt.Fprintf(out, format+"\n", args...)
+	}
+}
+
+// ReportErr prints a verification failure for a test whose result did not
+// match the expected value, and marks the run as failed.
+func ReportErr(category, test string, expected, got int64) {
+	Failf(category, test, "expected %d, got %d", expected, got)
+}
+
+// Failf prints a verification failure for a test that can't be described
+// by a single expected value, such as an unsorted sort result. Like
+// ReportErr, it marks the run as failed; see Finish.
+func Failf(category, test, format string, args ...any) {
+	failed.Store(true)
+	msg := fmt.Sprintf(format, args...)
+	if jsonOutput {
+		printJSON(struct {
+			Category string `json:"category"`
+			Test     string `json:"test"`
+			Error    string `json:"error"`
+		}{category, test, msg})
+		return
+	}
+	fmt.Fprintf(out, "ERROR:%s:%s: %s\n", category, test, msg)
+}
+
+func printJSON(v any) {
+	line, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchlib: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, string(line))
+}
@@ -0,0 +1,112 @@
+package benchlib
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportThroughput(t *testing.T) {
+	output := capture(t, func() {
+		ReportThroughput("fanout", "throughput-100k", 100000, 50*time.Millisecond)
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || lines[0] != "Throughput: 2000000 msg/sec" {
+		t.Fatalf("unexpected output %q", output)
+	}
+	rec, err := ParseLine(lines[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Test != "throughput-100k-throughput" || rec.Result != 2000000 || rec.TimeMs != 50 {
+		t.Errorf("ParseLine(%q) = %+v", lines[1], rec)
+	}
+}
+
+func TestReportThroughputZeroElapsed(t *testing.T) {
+	output := capture(t, func() {
+		ReportThroughput("pingpong", "roundtrip-100k", 10, 0)
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	rec, err := ParseLine(lines[len(lines)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Result != 0 {
+		t.Errorf("rate for zero elapsed = %d, want 0", rec.Result)
+	}
+}
+
+func TestReportPerOp(t *testing.T) {
+	output := capture(t, func() {
+		ReportPerOp("defer", "with", 4000, 5*time.Microsecond)
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || lines[0] != "Per op: 1.250 ns" {
+		t.Fatalf("unexpected output %q", output)
+	}
+	rec, err := ParseLine(lines[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Test != "with-per-op" || rec.Result != 1250 || rec.TimeNs != 5000 {
+		t.Errorf("ParseLine(%q) = %+v", lines[1], rec)
+	}
+}
+
+func TestNotefSkippedInJSONMode(t *testing.T) {
+	if got := capture(t, func() { Notef("energy %.3f", 0.5) }); got != "energy 0.500\n" {
+		t.Errorf("Notef printed %q", got)
+	}
+	jsonOutput = true
+	t.Cleanup(func() { jsonOutput = false })
+	if got := capture(t, func() { Notef("energy %.3f", 0.5) }); got != "" {
+		t.Errorf("Notef printed %q in JSON mode, want nothing", got)
+	}
+}
+
+func TestFlushSortsRecords(t *testing.T) {
+	var buf strings.Builder
+	savedOut, savedStream := out, streamOutput
+	out, streamOutput = &buf, false
+	t.Cleanup(func() { out, streamOutput = savedOut, savedStream })
+
+	Report("skynet", "spawn-only", 1, 0)
+	Report("fanout", "throughput-100k", 1, 0)
+	Report("skynet", "collect-only", 1, 0)
+	if buf.Len() != 0 {
+		t.Fatalf("records printed before Flush: %q", buf.String())
+	}
+	Flush()
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		rec, err := ParseLine(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, rec.Category+":"+rec.Test)
+	}
+	want := []string{"fanout:throughput-100k", "skynet:collect-only", "skynet:spawn-only"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Flush order = %v, want %v", got, want)
+	}
+
+	buf.Reset()
+	Flush()
+	if buf.Len() != 0 {
+		t.Errorf("second Flush printed %q, want nothing", buf.String())
+	}
+}
+
+func TestFailfMarksFailed(t *testing.T) {
+	failed.Store(false)
+	t.Cleanup(func() { failed.Store(false) })
+	if Failed() {
+		t.Fatal("Failed() = true before any failure")
+	}
+	capture(t, func() { ReportErr("primes", "count-10k", 1229, 1228) })
+	if !Failed() {
+		t.Error("Failed() = false after ReportErr")
+	}
+}
@@ -0,0 +1,106 @@
+package benchlib
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// defaultRuns is the repeat count used when BENCH_RUNS is unset.
+const defaultRuns = 5
+
+// Runs returns the number of times a multi-run benchmark should repeat,
+// taken from BENCH_RUNS and defaulting to 5.
+func Runs() int {
+	v := os.Getenv("BENCH_RUNS")
+	if v == "" {
+		return defaultRuns
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		fmt.Fprintf(os.Stderr, "benchlib: invalid BENCH_RUNS %q, using %d\n", v, defaultRuns)
+		return defaultRuns
+	}
+	return n
+}
+
+// RunN calls f n times and returns the duration of each call, in call
+// order, so samples[0] is always the cold first run.
+func RunN(n int, f func()) []time.Duration {
+	samples := make([]time.Duration, n)
+	for i := range samples {
+		start := time.Now()
+		f()
+		samples[i] = time.Since(start)
+	}
+	return samples
+}
+
+// ReportRuns prints the min, median and max of samples as three BENCH lines
+// named <test>-min, <test>-med and <test>-max. Each line carries the
+// coefficient of variation of the whole sample set as a trailing field so
+// noisy measurements can be rejected.
+func ReportRuns(category, test string, result int64, samples []time.Duration) {
+	if len(samples) == 0 {
+		return
+	}
+	cv := Summarize(samples).CVPct
+	report(newRecord(category, test+"-min", result, slices.Min(samples)).withCV(cv))
+	report(newRecord(category, test+"-med", result, Median(samples)).withCV(cv))
+	report(newRecord(category, test+"-max", result, slices.Max(samples)).withCV(cv))
+}
+
+// ReportColdSteady prints <test>-cold, the time of the first sample, and
+// <test>-steady, the mean of the rest, separating one-off startup costs
+// (page faults, stack and heap growth, scheduler warm-up) from the cost of
+// repeating the work. With a single sample there is no steady state, so
+// only the cold line is printed.
+func ReportColdSteady(category, test string, result int64, samples []time.Duration) {
+	if len(samples) == 0 {
+		return
+	}
+	Report(category, test+"-cold", result, samples[0])
+	if len(samples) > 1 {
+		Report(category, test+"-steady", result, Summarize(samples[1:]).Mean)
+	}
+}
+
+// DefaultMinTime is the measurement floor used by benchmarks that accept
+// -min-time.
+const DefaultMinTime = 100 * time.Millisecond
+
+// ScaleN returns how many back-to-back calls of f take at least min,
+// doubling from one call the way testing.B grows b.N. A min of zero or
+// less returns 1 without calling f.
+func ScaleN(min time.Duration, f func()) int {
+	n := 1
+	for min > 0 {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			f()
+		}
+		if time.Since(start) >= min {
+			break
+		}
+		n *= 2
+	}
+	return n
+}
+
+// RunMinTime is RunN for calls too fast to time one at a time. It sizes
+// a batch with ScaleN, times runs batches of that size, and returns the
+// per-call duration of each batch.
+func RunMinTime(runs int, min time.Duration, f func()) []time.Duration {
+	n := ScaleN(min, f)
+	samples := RunN(runs, func() {
+		for i := 0; i < n; i++ {
+			f()
+		}
+	})
+	for i := range samples {
+		samples[i] /= time.Duration(n)
+	}
+	return samples
+}
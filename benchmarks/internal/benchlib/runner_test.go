@@ -0,0 +1,58 @@
+package benchlib
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScaleN(t *testing.T) {
+	if n := ScaleN(0, func() { t.Fatal("f called with no floor") }); n != 1 {
+		t.Errorf("ScaleN(0) = %d, want 1", n)
+	}
+
+	calls := 0
+	n := ScaleN(5*time.Millisecond, func() {
+		calls++
+		time.Sleep(time.Millisecond)
+	})
+	// batches of 1, 2, 4, ... calls, stopping at the first to reach 5ms,
+	// which needs at least 5 one-millisecond sleeps
+	if n < 5 || n&(n-1) != 0 || calls != 2*n-1 {
+		t.Errorf("ScaleN = %d after %d calls, want a power of two >= 5 after 2n-1 calls", n, calls)
+	}
+}
+
+func TestRunMinTime(t *testing.T) {
+	samples := RunMinTime(3, 2*time.Millisecond, func() { time.Sleep(time.Millisecond) })
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want 3", len(samples))
+	}
+	for _, s := range samples {
+		if s < time.Millisecond {
+			t.Errorf("per-call sample %v, want at least the 1ms sleep", s)
+		}
+	}
+}
+
+func TestReportColdSteady(t *testing.T) {
+	output := capture(t, func() {
+		ReportColdSteady("skynet", "spawn-100k", 7, []time.Duration{90, 10, 20, 30})
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q", output)
+	}
+	for i, want := range []struct {
+		test string
+		ns   int64
+	}{{"spawn-100k-cold", 90}, {"spawn-100k-steady", 20}} {
+		rec, err := ParseLine(lines[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Test != want.test || rec.TimeNs != want.ns || rec.Result != 7 {
+			t.Errorf("line %d = %+v, want %s in %dns", i, rec, want.test, want.ns)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package benchlib
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Stats summarizes a set of timing samples.
+type Stats struct {
+	Mean   time.Duration
+	StdDev time.Duration
+	// CVPct is the coefficient of variation, StdDev/Mean as a percentage.
+	CVPct float64
+}
+
+// Median returns the middle sample, or the mean of the two middle samples
+// when there is an even number of them. samples is not modified.
+func Median(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// Summarize computes the mean, sample standard deviation and coefficient
+// of variation of samples. Fewer than two samples have no spread, so
+// StdDev and CVPct are reported as 0 rather than NaN.
+func Summarize(samples []time.Duration) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s)
+	}
+	mean := sum / float64(len(samples))
+	if len(samples) < 2 || mean == 0 {
+		return Stats{Mean: time.Duration(mean)}
+	}
+	var sq float64
+	for _, s := range samples {
+		d := float64(s) - mean
+		sq += d * d
+	}
+	stddev := math.Sqrt(sq / float64(len(samples)-1))
+	return Stats{
+		Mean:   time.Duration(mean),
+		StdDev: time.Duration(stddev),
+		CVPct:  stddev / mean * 100,
+	}
+}
@@ -0,0 +1,61 @@
+package benchlib
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    time.Duration
+	}{
+		{"empty", nil, 0},
+		{"single", []time.Duration{7}, 7},
+		{"odd", []time.Duration{9, 1, 5}, 5},
+		{"even", []time.Duration{4, 1, 10, 2}, 3},
+		{"even pair", []time.Duration{2, 4}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Median(tt.samples); got != tt.want {
+				t.Errorf("Median(%v) = %v, want %v", tt.samples, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedianLeavesInputUnsorted(t *testing.T) {
+	samples := []time.Duration{3, 1, 2}
+	Median(samples)
+	if samples[0] != 3 || samples[1] != 1 || samples[2] != 2 {
+		t.Errorf("Median reordered its input: %v", samples)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]time.Duration{2, 4, 4, 4, 5, 5, 7, 9})
+	if s.Mean != 5 {
+		t.Errorf("Mean = %v, want 5", s.Mean)
+	}
+	// Sample variance is 32/7.
+	wantStd := math.Sqrt(32.0 / 7.0)
+	if s.StdDev != time.Duration(wantStd) {
+		t.Errorf("StdDev = %v, want %v", s.StdDev, time.Duration(wantStd))
+	}
+	if want := wantStd / 5 * 100; math.Abs(s.CVPct-want) > 1e-9 {
+		t.Errorf("CVPct = %v, want %v", s.CVPct, want)
+	}
+}
+
+func TestSummarizeSingleSample(t *testing.T) {
+	s := Summarize([]time.Duration{42})
+	if s.Mean != 42 || s.StdDev != 0 || s.CVPct != 0 {
+		t.Errorf("Summarize(single) = %+v, want mean 42 and no spread", s)
+	}
+	if math.IsNaN(s.CVPct) {
+		t.Error("CVPct is NaN")
+	}
+}
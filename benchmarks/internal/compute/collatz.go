@@ -0,0 +1,80 @@
+package compute
+
+import (
+	"flag"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultCollatzLimit = 1000000
+	// Below one million, 837799 takes the most steps to reach 1: 524.
+	collatzMaxStart = 837799
+	collatzMaxSteps = 524
+)
+
+// collatzSteps returns how many steps of n -> n/2 (n even) or 3n+1 (n odd)
+// it takes to reach 1.
+func collatzSteps(n int64) int64 {
+	var steps int64
+	for n != 1 {
+		if n%2 == 0 {
+			n /= 2
+		} else {
+			n = 3*n + 1
+		}
+		steps++
+	}
+	return steps
+}
+
+// longestCollatz returns the start below limit with the most steps, and
+// that step count. Ties go to the smaller start.
+func longestCollatz(limit int64) (start, steps int64) {
+	for n := int64(1); n < limit; n++ {
+		if s := collatzSteps(n); s > steps {
+			start, steps = n, s
+		}
+	}
+	return start, steps
+}
+
+func init() {
+	benchlib.Register("collatz", runCollatz, "cpu")
+}
+
+// runCollatz runs the Collatz benchmark.
+// Output format: BENCH:collatz:<test>:<result>:<time_ms>:<time_ns>
+//
+// Walks the Collatz sequence of every start below -limit (1,000,000 by
+// default), with no memoization, and finds the longest. max-steps-<limit>
+// reports its step count and the search time; max-start-<limit> reports
+// the start with a zero time, so the search is only counted once. For the
+// default limit they must be 524 and 837799.
+func runCollatz(args []string) {
+	fs := flag.NewFlagSet("collatz", flag.ExitOnError)
+	limit := fs.Int64("limit", defaultCollatzLimit, "search starts below this value")
+	fs.Parse(args)
+	if *limit < 2 {
+		benchlib.Usagef("collatz", "-limit must be at least 2, got %d", *limit)
+		return
+	}
+	benchlib.ReportEnv()
+
+	begin := time.Now()
+	start, steps := longestCollatz(*limit)
+	elapsed := time.Since(begin)
+
+	size := benchlib.SizeName(*limit)
+	benchlib.Report("collatz", "max-steps-"+size, steps, elapsed)
+	benchlib.Report("collatz", "max-start-"+size, start, 0)
+	if *limit == defaultCollatzLimit {
+		if start != collatzMaxStart {
+			benchlib.ReportErr("collatz", "max-start-"+size, collatzMaxStart, start)
+		}
+		if steps != collatzMaxSteps {
+			benchlib.ReportErr("collatz", "max-steps-"+size, collatzMaxSteps, steps)
+		}
+	}
+}
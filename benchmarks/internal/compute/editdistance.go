@@ -0,0 +1,90 @@
+package compute
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultEditLength = 2000
+	// editSeedA and editSeedB fix the two inputs; they don't follow
+	// BENCH_SEED, so the distance stays a known constant.
+	editSeedA = 1
+	editSeedB = 2
+	// editDistance2000 is the distance between the default-length inputs.
+	editDistance2000 = 1048
+)
+
+// editAlphabet is small so the two strings share plenty of characters and
+// the distance depends on the alignment, not just the length.
+const editAlphabet = "acgt"
+
+// editString returns n characters of editAlphabet drawn from an LCG
+// seeded with seed.
+func editString(n int, seed uint64) string {
+	rng := benchlib.NewLCG(seed)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = editAlphabet[(rng.Next()>>33)%uint64(len(editAlphabet))]
+	}
+	return string(b)
+}
+
+// levenshtein returns the edit distance between a and b, filling the whole
+// (len(a)+1) x (len(b)+1) table of prefix distances.
+func levenshtein(a, b string) int {
+	cols := len(b) + 1
+	d := make([]int, (len(a)+1)*cols)
+	for j := 0; j < cols; j++ {
+		d[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		row, prev := i*cols, (i-1)*cols
+		d[row] = i
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[row+j] = min(d[prev+j]+1, d[row+j-1]+1, d[prev+j-1]+cost)
+		}
+	}
+	return d[len(d)-1]
+}
+
+func init() {
+	benchlib.Register("editdistance", runEditDistance, "cpu")
+}
+
+// runEditDistance runs the edit distance benchmark.
+// Output format: BENCH:editdistance:<test>:<result>:<time_ms>:<time_ns>
+//
+// Computes the Levenshtein distance between two -n character strings
+// (2,000 by default) over a four-letter alphabet, generated from the
+// shared LCG with fixed seeds, using the classic dynamic programming
+// table. levenshtein-<n> reports the distance, which for the default
+// length must be editDistance2000.
+func runEditDistance(args []string) {
+	fs := flag.NewFlagSet("editdistance", flag.ExitOnError)
+	n := fs.Int("n", defaultEditLength, "length of each string")
+	fs.Parse(args)
+	if *n < 1 {
+		benchlib.Usagef("editdistance", "-n must be at least 1, got %d", *n)
+		return
+	}
+	benchlib.ReportEnv()
+
+	a, b := editString(*n, editSeedA), editString(*n, editSeedB)
+	test := fmt.Sprintf("levenshtein-%d", *n)
+	start := time.Now()
+	dist := levenshtein(a, b)
+	elapsed := time.Since(start)
+
+	benchlib.Report("editdistance", test, int64(dist), elapsed)
+	if *n == defaultEditLength && dist != editDistance2000 {
+		benchlib.ReportErr("editdistance", test, editDistance2000, int64(dist))
+	}
+}
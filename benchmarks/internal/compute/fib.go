@@ -0,0 +1,223 @@
+package compute
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func fibNaive(n int64) int64 {
+	if n < 2 {
+		return n
+	}
+	return fibNaive(n-1) + fibNaive(n-2)
+}
+
+// overflowed is returned by fibFast when fib(n) doesn't fit in an int64
+// (n > 92). Fibonacci numbers are never negative, so it can't be mistaken
+// for a result.
+const overflowed = -1
+
+func fibFast(n int64) int64 {
+	if n < 2 {
+		return n
+	}
+	a, b := int64(0), int64(1)
+	for i := int64(1); i < n; i++ {
+		if b > math.MaxInt64-a {
+			return overflowed
+		}
+		a, b = b, a+b
+	}
+	return b
+}
+
+// fibTailRec is fibFast in accumulator-passing style. Go doesn't
+// eliminate tail calls, so each step still costs a stack frame; runtimes
+// that do should run it as fast as the loop.
+func fibTailRec(n int64) int64 {
+	if n < 2 {
+		return n
+	}
+	return fibAcc(n-1, 0, 1)
+}
+
+// fibAcc advances the pair (a, b) = (fib(k-1), fib(k)) n more steps and
+// returns the final b. The recursive call is in tail position.
+func fibAcc(n, a, b int64) int64 {
+	if n == 0 {
+		return b
+	}
+	return fibAcc(n-1, b, a+b)
+}
+
+// fibBig is fibFast with math/big.Int, so it never overflows.
+func fibBig(n int64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := int64(0); i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+// bigChecksum reduces a number too large for the result field to the
+// FNV-1a hash of its decimal digits, masked to 63 bits so it stays
+// positive.
+func bigChecksum(x *big.Int) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(x.String()))
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
+// fib(1000) has 209 digits; fibBig's output is checked against its ends.
+const (
+	fib1000Digits = 209
+	fib1000Head   = "434665576869"
+	fib1000Tail   = "166849228875"
+	// fib1000Checksum is bigChecksum(fib(1000)).
+	fib1000Checksum = 2417715034351820206
+)
+
+// fibMemo is naive recursion with a memo table. The table is allocated on
+// every call so repeated runs pay for the allocation too.
+func fibMemo(n int64) int64 {
+	if n < 2 {
+		return n
+	}
+	// fib(k) > 0 for k >= 1, so a zero entry means "not computed yet"
+	cache := make([]int64, n+1)
+	var memo func(k int64) int64
+	memo = func(k int64) int64 {
+		if k < 2 {
+			return k
+		}
+		if cache[k] != 0 {
+			return cache[k]
+		}
+		cache[k] = memo(k-1) + memo(k-2)
+		return cache[k]
+	}
+	return memo(n)
+}
+
+// warmupPasses is the number of untimed calls made before measuring, so
+// the timed section doesn't pay for cold caches and scheduler startup.
+const warmupPasses = 3
+
+// unverified marks a test whose expected result isn't known, such as a
+// naive fib at a depth chosen on the command line.
+const unverified = -1
+
+// defaultDepth is the naive fib depth used when -n is absent.
+const defaultDepth = 35
+
+// benchFib reports the min, median and max time of one call of f(n). A
+// positive minTime batches calls until each sample takes at least that
+// long, for cases too fast to time a call at a time.
+func benchFib(name string, n int64, warmup int, minTime time.Duration, expected int64, f func(int64) int64) {
+	for i := 0; i < warmup; i++ {
+		f(n)
+	}
+	var result int64
+	samples := benchlib.RunMinTime(benchlib.Runs(), minTime, func() { result = f(n) })
+	if result == overflowed {
+		benchlib.Failf("fibonacci", name, "fib(%d) overflows int64", n)
+		return
+	}
+	benchlib.ReportRuns("fibonacci", name, result, samples)
+	if expected != unverified && result != expected {
+		benchlib.ReportErr("fibonacci", name, expected, result)
+	}
+}
+
+func benchFibRepeated(name string, n int64, iterations, warmup int, expected int64, f func(int64) int64) {
+	for i := 0; i < warmup; i++ {
+		f(n)
+	}
+	start := time.Now()
+	var result int64
+	for i := 0; i < iterations; i++ {
+		result = f(n)
+	}
+	elapsed := time.Since(start)
+	if result == overflowed {
+		benchlib.Failf("fibonacci", name, "fib(%d) overflows int64", n)
+		return
+	}
+	benchlib.Report("fibonacci", name, result, elapsed)
+	if result != expected {
+		benchlib.ReportErr("fibonacci", name, expected, result)
+	}
+}
+
+func init() {
+	benchlib.Register("fibonacci", runFib, "cpu")
+}
+
+// runFib runs the fibonacci benchmark.
+// Output format: BENCH:fibonacci:<test>:<result>:<time_ms>:<time_ns>
+//
+// Single-call tests run BENCH_RUNS times and report -min, -med and -max
+// lines with a trailing <cv_pct> field. The iterative and tail-recursive
+// tests finish in nanoseconds, so each of their samples repeats the call
+// until it has run for -min-time and reports the per-call time; so does
+// fib-big-1000, whose result is the FNV-1a checksum of fib(1000)'s digits.
+// -fast=<n> adds fib-fast-<n>; fib(93) and beyond overflow int64, so that
+// test prints an ERROR line instead of a wrapped-around result.
+func runFib(args []string) {
+	fs := flag.NewFlagSet("fibonacci", flag.ExitOnError)
+	depth := fs.Int64("n", defaultDepth, "depth of the deeper naive recursive test")
+	minTime := fs.Duration("min-time", benchlib.DefaultMinTime, "repeat fast tests until each sample takes at least this long")
+	fastN := fs.Int64("fast", 0, "also run fib-fast-<n> for this n; from 93 on it fails because fib(n) overflows int64")
+	fs.Parse(args)
+	benchlib.ReportEnv()
+
+	// Naive recursive tests
+	benchFib("fib-naive-30", 30, warmupPasses, 0, 832040, fibNaive)
+	var expected int64 = unverified
+	if *depth == defaultDepth {
+		expected = 9227465
+	}
+	benchFib(fmt.Sprintf("fib-naive-%d", *depth), *depth, warmupPasses, 0, expected, fibNaive)
+
+	// Iterative tests
+	benchFib("fib-fast-30", 30, 0, *minTime, 832040, fibFast)
+	benchFib("fib-fast-50", 50, 0, *minTime, 12586269025, fibFast)
+	benchFib("fib-fast-70", 70, 0, *minTime, 190392490709135, fibFast)
+	if *fastN > 0 {
+		expected := int64(overflowed)
+		if want := fibBig(*fastN); want.IsInt64() {
+			expected = want.Int64()
+		}
+		benchFib(fmt.Sprintf("fib-fast-%d", *fastN), *fastN, 0, *minTime, expected, fibFast)
+	}
+
+	// Tail-recursive test, to compare with fib-fast-50
+	benchFib("fib-tailrec-50", 50, 0, *minTime, 12586269025, fibTailRec)
+
+	// Arbitrary precision, reported as a checksum of the digits
+	var big1000 *big.Int
+	samples := benchlib.RunMinTime(benchlib.Runs(), *minTime, func() { big1000 = fibBig(1000) })
+	benchlib.ReportRuns("fibonacci", "fib-big-1000", bigChecksum(big1000), samples)
+	if digits := big1000.String(); len(digits) != fib1000Digits ||
+		!strings.HasPrefix(digits, fib1000Head) || !strings.HasSuffix(digits, fib1000Tail) {
+		benchlib.Failf("fibonacci", "fib-big-1000", "expected %d digits %s...%s, got %d digits %s",
+			fib1000Digits, fib1000Head, fib1000Tail, len(digits), digits)
+	} else if sum := bigChecksum(big1000); sum != fib1000Checksum {
+		benchlib.ReportErr("fibonacci", "fib-big-1000", fib1000Checksum, sum)
+	}
+
+	// Memoized tests
+	benchFib("fib-memo-40", 40, warmupPasses, 0, 102334155, fibMemo)
+
+	// Repeated runs
+	benchFibRepeated("fib-naive-20-x1000", 20, 1000, warmupPasses, 6765, fibNaive)
+	benchFibRepeated("fib-fast-20-x1000", 20, 1000, warmupPasses, 6765, fibFast)
+}
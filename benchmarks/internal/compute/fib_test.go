@@ -0,0 +1,23 @@
+package compute
+
+import "testing"
+
+func TestFibFastOverflow(t *testing.T) {
+	// fib(92) is the largest Fibonacci number that fits in an int64
+	if got, want := fibFast(92), int64(7540113804746346429); got != want {
+		t.Errorf("fibFast(92) = %d, want %d", got, want)
+	}
+	for _, n := range []int64{93, 94, 200} {
+		if got := fibFast(n); got != overflowed {
+			t.Errorf("fibFast(%d) = %d, want the overflowed sentinel", n, got)
+		}
+	}
+}
+
+func TestFibFastAgreesWithFibBig(t *testing.T) {
+	for n := int64(0); n <= 92; n++ {
+		if got, want := fibFast(n), fibBig(n); !want.IsInt64() || got != want.Int64() {
+			t.Errorf("fibFast(%d) = %d, want %s", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package compute
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	ioTotalBytes   = 100 << 20
+	defaultIOChunk = 16
+)
+
+// writeChunks writes total bytes to w in chunk-sized writes and returns
+// the number of bytes w accepted.
+func writeChunks(w io.Writer, chunk []byte, total int64) (int64, error) {
+	var written int64
+	for written < total {
+		p := chunk
+		if rem := total - written; rem < int64(len(p)) {
+			p = p[:rem]
+		}
+		n, err := w.Write(p)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func init() {
+	benchlib.Register("io", runIO, "io")
+}
+
+// runIO runs the buffered-writer benchmark.
+// Output format: BENCH:io:<test>:<result>:<time_ms>:<time_ns>
+//
+// Writes 100MB in -chunk byte writes (16 by default), through a
+// bufio.Writer and directly, first to io.Discard and then to os.DevNull.
+// io.Discard keeps the kernel out entirely, so its two tests compare
+// bufio's copying with bare Write calls; on os.DevNull every direct write
+// is a syscall, which is the amplification buffering exists to avoid.
+// No disk is involved either way. The result is the number of bytes
+// written, which must be 100MB, and a -throughput line reports MB per
+// second.
+func runIO(args []string) {
+	fs := flag.NewFlagSet("io", flag.ExitOnError)
+	chunkSize := fs.Int("chunk", defaultIOChunk, "bytes per Write call")
+	fs.Parse(args)
+	if *chunkSize < 1 {
+		benchlib.Usagef("io", "-chunk must be at least 1, got %d", *chunkSize)
+		return
+	}
+	benchlib.ReportEnv()
+
+	chunk := make([]byte, *chunkSize)
+	for i := range chunk {
+		chunk[i] = byte('a' + i%26)
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		benchlib.Failf("io", "devnull", "open %s: %v", os.DevNull, err)
+		return
+	}
+	defer devNull.Close()
+
+	for _, tc := range []struct {
+		test     string
+		dst      io.Writer
+		buffered bool
+	}{
+		{"bufio-write-100mb", io.Discard, true},
+		{"direct-write-100mb", io.Discard, false},
+		{"bufio-devnull-100mb", devNull, true},
+		{"direct-devnull-100mb", devNull, false},
+	} {
+		start := time.Now()
+		var written int64
+		var err error
+		if tc.buffered {
+			bw := bufio.NewWriter(tc.dst)
+			written, err = writeChunks(bw, chunk, ioTotalBytes)
+			if err == nil {
+				err = bw.Flush()
+			}
+		} else {
+			written, err = writeChunks(tc.dst, chunk, ioTotalBytes)
+		}
+		elapsed := time.Since(start)
+
+		if err != nil {
+			benchlib.Failf("io", tc.test, "write: %v", err)
+			continue
+		}
+		benchlib.Report("io", tc.test, written, elapsed)
+		benchlib.ReportRate("io", tc.test, written>>20, "MB", elapsed)
+		if written != ioTotalBytes {
+			benchlib.ReportErr("io", tc.test, ioTotalBytes, written)
+		}
+	}
+}
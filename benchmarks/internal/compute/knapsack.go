@@ -0,0 +1,110 @@
+package compute
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultKnapsackItems    = 1000
+	defaultKnapsackCapacity = 5000
+	// knapsackMaxWeight and knapsackMaxValue bound the generated items.
+	knapsackMaxWeight = 100
+	knapsackMaxValue  = 1000
+	// knapsackBest is the optimal value for the default items, capacity
+	// and seed.
+	knapsackBest = 188333
+)
+
+// knapsackItems returns n weights in [1, knapsackMaxWeight] and values in
+// [1, knapsackMaxValue], drawn alternately from an LCG seeded with seed.
+func knapsackItems(n int, seed uint64) (weights, values []int) {
+	rng := benchlib.NewLCG(seed)
+	weights, values = make([]int, n), make([]int, n)
+	for i := range weights {
+		weights[i] = 1 + int((rng.Next()>>33)%knapsackMaxWeight)
+		values[i] = 1 + int((rng.Next()>>33)%knapsackMaxValue)
+	}
+	return weights, values
+}
+
+// knapsack solves the 0/1 knapsack problem with the classic table, where
+// row i column c holds the best value of the first i items within weight
+// c. It returns the best value and the chosen items, traced back through
+// the table.
+func knapsack(weights, values []int, capacity int) (best int, chosen []int) {
+	cols := capacity + 1
+	t := make([]int, (len(weights)+1)*cols)
+	for i := 1; i <= len(weights); i++ {
+		w, v := weights[i-1], values[i-1]
+		row, prev := i*cols, (i-1)*cols
+		for c := 0; c < cols; c++ {
+			t[row+c] = t[prev+c]
+			if c >= w && t[prev+c-w]+v > t[row+c] {
+				t[row+c] = t[prev+c-w] + v
+			}
+		}
+	}
+	c := capacity
+	for i := len(weights); i > 0; i-- {
+		if t[i*cols+c] != t[(i-1)*cols+c] {
+			chosen = append(chosen, i-1)
+			c -= weights[i-1]
+		}
+	}
+	return t[len(t)-1], chosen
+}
+
+func init() {
+	benchlib.Register("knapsack", runKnapsack, "cpu")
+}
+
+// runKnapsack runs the knapsack benchmark.
+// Output format: BENCH:knapsack:<test>:<result>:<time_ms>:<time_ns>
+//
+// Solves a 0/1 knapsack of -items items (1,000 by default) with weights up
+// to 100 and values up to 1,000 from the shared LCG (seed from
+// BENCH_SEED), for a -capacity of 5,000 by default, over the full dynamic
+// programming table. best-<items>x<capacity> reports the optimal value;
+// the items traced back from the table must fit and add up to it, and for
+// the default sizes and seed it must be knapsackBest.
+func runKnapsack(args []string) {
+	fs := flag.NewFlagSet("knapsack", flag.ExitOnError)
+	items := fs.Int("items", defaultKnapsackItems, "number of items")
+	capacity := fs.Int("capacity", defaultKnapsackCapacity, "knapsack weight capacity")
+	fs.Parse(args)
+	if *items < 1 {
+		benchlib.Usagef("knapsack", "-items must be at least 1, got %d", *items)
+		return
+	}
+	if *capacity < 0 {
+		benchlib.Usagef("knapsack", "-capacity must not be negative, got %d", *capacity)
+		return
+	}
+	benchlib.ReportEnv()
+
+	seed := benchlib.Seed()
+	weights, values := knapsackItems(*items, seed)
+	test := fmt.Sprintf("best-%dx%d", *items, *capacity)
+	start := time.Now()
+	best, chosen := knapsack(weights, values, *capacity)
+	elapsed := time.Since(start)
+
+	benchlib.Report("knapsack", test, int64(best), elapsed)
+	var weight, value int
+	for _, i := range chosen {
+		weight += weights[i]
+		value += values[i]
+	}
+	if weight > *capacity || value != best {
+		benchlib.Failf("knapsack", test, "chosen items weigh %d (capacity %d) and are worth %d, want %d",
+			weight, *capacity, value, best)
+	}
+	if *items == defaultKnapsackItems && *capacity == defaultKnapsackCapacity &&
+		seed == benchlib.DefaultSeed && best != knapsackBest {
+		benchlib.ReportErr("knapsack", test, knapsackBest, int64(best))
+	}
+}
@@ -0,0 +1,120 @@
+package compute
+
+import (
+	"flag"
+	"math"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultIters = 100_000_000
+	// maxError is the accuracy threshold at the default -iters.
+	maxError = 1e-7
+	// errorFactor times 1/n is the accuracy a shorter run can reach.
+	errorFactor = 10
+	// maxKahanError bounds the rounding error of the compensated sum.
+	maxKahanError = 1e-10
+	// errorScale converts the error to the integer result field.
+	errorScale = 1e15
+)
+
+func leibnizPi(n int64) float64 {
+	sum := 0.0
+	sign := 1.0
+	for k := int64(0); k < n; k++ {
+		sum += sign / float64(2*k+1)
+		sign = -sign
+	}
+	return 4 * sum
+}
+
+// leibnizPiKahan is leibnizPi with Kahan compensated summation.
+func leibnizPiKahan(n int64) float64 {
+	sum := 0.0
+	comp := 0.0
+	sign := 1.0
+	for k := int64(0); k < n; k++ {
+		y := sign/float64(2*k+1) - comp
+		t := sum + y
+		comp = (t - sum) - y
+		sum = t
+		sign = -sign
+	}
+	return 4 * sum
+}
+
+// truncatedPi approximates 4 times the sum of the first n terms: the
+// series' tail beyond term n is (-1)^n * (1/n - 1/(4n^3)) plus a term of
+// order 1/n^5, which kahanTolerance allows for.
+func truncatedPi(n int64) float64 {
+	x := float64(n)
+	tail := 1/x - 1/(4*x*x*x)
+	if n%2 == 0 {
+		return math.Pi - tail
+	}
+	return math.Pi + tail
+}
+
+// kahanTolerance is the allowed gap between the compensated sum and
+// truncatedPi: maxKahanError plus the expansion term truncatedPi omits.
+func kahanTolerance(n int64) float64 {
+	x := float64(n)
+	return maxKahanError + 1/(x*x*x*x*x)
+}
+
+func init() {
+	benchlib.Register("leibniz_pi", runLeibniz, "cpu", "float")
+}
+
+// runLeibniz runs the Leibniz pi benchmark.
+// Output format: BENCH:leibniz_pi:<test>:<result>:<time_ms>:<time_ns>
+//
+// Approximates pi with the first -iters terms (default 100M) of the Leibniz
+// series 4 * (1 - 1/3 + 1/5 - 1/7 + ...). The result field is the absolute
+// error against math.Pi scaled by 1e15, so float64 divergence between
+// runtimes shows up even when every runtime passes the coarse accuracy
+// threshold of 1e-7. The series converges like 1/n, so runs with fewer
+// than 100M terms can't reach 1e-7 and are held to 10/n instead.
+//
+// leibniz-pi-kahan sums the same terms with Kahan compensated summation.
+// Its error against math.Pi is still dominated by truncating the series,
+// so its accuracy is checked against the truncated series value,
+// isolating the rounding error the compensation removes.
+func runLeibniz(args []string) {
+	fs := flag.NewFlagSet("leibniz_pi", flag.ExitOnError)
+	iters := fs.Int64("iters", defaultIters, "number of series terms to sum")
+	fs.Parse(args)
+	if *iters < 1 {
+		benchlib.Usagef("leibniz_pi", "-iters must be at least 1, got %d", *iters)
+		return
+	}
+	benchlib.ReportEnv()
+
+	threshold := math.Max(maxError, errorFactor/float64(*iters))
+	test := "leibniz-pi-" + benchlib.SizeName(*iters)
+	start := time.Now()
+	pi := leibnizPi(*iters)
+	elapsed := time.Since(start)
+
+	errMag := math.Abs(pi - math.Pi)
+	benchlib.Notef("pi ~= %.15f (error %.3e)", pi, errMag)
+	benchlib.Report("leibniz_pi", test, int64(errMag*errorScale), elapsed)
+	if errMag >= threshold {
+		benchlib.Failf("leibniz_pi", test, "error %.3e exceeds %.3e", errMag, threshold)
+	}
+
+	test = "leibniz-pi-kahan-" + benchlib.SizeName(*iters)
+	start = time.Now()
+	pi = leibnizPiKahan(*iters)
+	elapsed = time.Since(start)
+
+	errMag = math.Abs(pi - math.Pi)
+	rounding := math.Abs(pi - truncatedPi(*iters))
+	benchlib.Notef("pi ~= %.15f (error %.3e, rounding error %.3e)", pi, errMag, rounding)
+	benchlib.Report("leibniz_pi", test, int64(errMag*errorScale), elapsed)
+	if tol := kahanTolerance(*iters); rounding >= tol {
+		benchlib.Failf("leibniz_pi", test, "rounding error %.3e exceeds %.3e", rounding, tol)
+	}
+}
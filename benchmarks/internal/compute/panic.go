@@ -0,0 +1,106 @@
+package compute
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const defaultPanicIters = 1000000
+
+var errStep = errors.New("step failed")
+
+// failStep fails by panicking. It and failStepErr are kept out of line
+// so each iteration pays for a real call, as it would in a program where
+// the failing code lives elsewhere.
+//
+//go:noinline
+func failStep(i int64) {
+	panic(i)
+}
+
+// failStepErr fails by returning an error.
+//
+//go:noinline
+func failStepErr(i int64) error {
+	return errStep
+}
+
+// recoverStep calls failStep under a deferred recover and reports whether
+// it caught the panic.
+func recoverStep(i int64) (recovered bool) {
+	defer func() {
+		if recover() != nil {
+			recovered = true
+		}
+	}()
+	failStep(i)
+	return false
+}
+
+// recoverLoop runs n failing steps with panic and recover, returning how
+// many panics were recovered.
+func recoverLoop(n int64) int64 {
+	var count int64
+	for i := int64(0); i < n; i++ {
+		if recoverStep(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// errorLoop runs n failing steps with error returns, returning how many
+// errors were seen.
+func errorLoop(n int64) int64 {
+	var count int64
+	for i := int64(0); i < n; i++ {
+		if err := failStepErr(i); err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func init() {
+	benchlib.Register("panic", runPanic, "cpu")
+}
+
+// runPanic runs the panic-recovery benchmark.
+// Output format: BENCH:panic:<test>:<result>:<time_ms>:<time_ns>
+//
+// Runs -n steps (1,000,000 by default) that each fail, once signalling
+// the failure with panic and catching it with a deferred recover
+// (recover-loop) and once returning an error (error-return). The result
+// is the number of failures handled, which must be n, and a -throughput
+// line reports steps per second.
+func runPanic(args []string) {
+	fs := flag.NewFlagSet("panic", flag.ExitOnError)
+	n := fs.Int64("n", defaultPanicIters, "number of failing steps")
+	fs.Parse(args)
+	if *n < 1 {
+		benchlib.Usagef("panic", "-n must be at least 1, got %d", *n)
+		return
+	}
+	benchlib.ReportEnv()
+
+	for _, tc := range []struct {
+		test string
+		loop func(int64) int64
+	}{
+		{"recover-loop", recoverLoop},
+		{"error-return", errorLoop},
+	} {
+		start := time.Now()
+		handled := tc.loop(*n)
+		elapsed := time.Since(start)
+
+		benchlib.Report("panic", tc.test, handled, elapsed)
+		benchlib.ReportRate("panic", tc.test, handled, "ops", elapsed)
+		if handled != *n {
+			benchlib.ReportErr("panic", tc.test, *n, handled)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package compute
+
+import (
+	"flag"
+	"math"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultLimit = 100000
+	// primesBelowDefault is the prime count up to defaultLimit; it only
+	// holds for that limit, so other sizes are reported unverified.
+	primesBelowDefault = 9592
+
+	nthIndex = 100000
+	// nthPrimeExpected is the 100,000th prime.
+	nthPrimeExpected = 1299709
+
+	gapLimit = 1000000
+	// maxGapBelowGapLimit is the largest gap between consecutive primes
+	// below one million, between 492113 and 492227.
+	maxGapBelowGapLimit = 114
+)
+
+func isPrime(n int64) bool {
+	if n < 2 {
+		return false
+	}
+	if n == 2 {
+		return true
+	}
+	if n%2 == 0 {
+		return false
+	}
+	for i := int64(3); i*i <= n; i += 2 {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func countPrimes(limit int64) int64 {
+	var count int64 = 0
+	for n := int64(2); n <= limit; n++ {
+		if isPrime(n) {
+			count++
+		}
+	}
+	return count
+}
+
+// sieve returns composite[n] for 0 <= n <= limit, marked with a sieve of
+// Eratosthenes. Entries 0 and 1 are left false; callers start at 2.
+func sieve(limit int64) []bool {
+	composite := make([]bool, limit+1)
+	for n := int64(2); n*n <= limit; n++ {
+		if composite[n] {
+			continue
+		}
+		for m := n * n; m <= limit; m += n {
+			composite[m] = true
+		}
+	}
+	return composite
+}
+
+// countPrimesSieve counts primes up to and including limit with a sieve of
+// Eratosthenes, for comparison against trial division.
+func countPrimesSieve(limit int64) int64 {
+	if limit < 2 {
+		return 0
+	}
+	var count int64 = 0
+	for _, c := range sieve(limit)[2:] {
+		if !c {
+			count++
+		}
+	}
+	return count
+}
+
+// maxPrimeGap returns the largest difference between consecutive primes
+// up to and including limit, walking the sieve in order and remembering
+// the previous prime.
+func maxPrimeGap(limit int64) int64 {
+	if limit < 3 {
+		return 0
+	}
+	composite := sieve(limit)
+	var maxGap int64 = 0
+	prev := int64(2)
+	for n := int64(3); n <= limit; n += 2 {
+		if composite[n] {
+			continue
+		}
+		if gap := n - prev; gap > maxGap {
+			maxGap = gap
+		}
+		prev = n
+	}
+	return maxGap
+}
+
+// nthPrime returns the nth prime (nthPrime(1) == 2). It sieves up to an
+// estimate of the nth prime's size and doubles the bound until the sieve
+// holds n primes.
+func nthPrime(n int64) int64 {
+	if n < 1 {
+		return 0
+	}
+	// p_n < n(ln n + ln ln n) for n >= 6
+	bound := int64(15)
+	if n >= 6 {
+		ln := math.Log(float64(n))
+		bound = int64(float64(n) * (ln + math.Log(ln)))
+	}
+	for {
+		composite := sieve(bound)
+		var count int64 = 0
+		for p := int64(2); p <= bound; p++ {
+			if composite[p] {
+				continue
+			}
+			if count++; count == n {
+				return p
+			}
+		}
+		bound *= 2
+	}
+}
+
+func init() {
+	benchlib.Register("primes", runPrimes, "cpu")
+}
+
+// runPrimes runs the primes benchmark.
+// Output format: BENCH:primes:<test>:<result>:<time_ms>:<time_ns>
+//
+// Each test runs BENCH_RUNS times (default 5) and reports -min, -med and
+// -max lines with a trailing <cv_pct> field.
+func runPrimes(args []string) {
+	fs := flag.NewFlagSet("primes", flag.ExitOnError)
+	limit := fs.Int64("limit", defaultLimit, "upper bound for the count-<limit> test")
+	fs.Parse(args)
+	benchlib.ReportEnv()
+
+	runs := benchlib.Runs()
+
+	// count-primes-10k
+	var result int64
+	samples := benchlib.RunN(runs, func() { result = countPrimes(10000) })
+	benchlib.ReportRuns("primes", "count-10k", result, samples)
+	if result != 1229 {
+		benchlib.ReportErr("primes", "count-10k", 1229, result)
+	}
+
+	// count-primes-<limit>
+	test := "count-" + benchlib.SizeName(*limit)
+	samples = benchlib.RunN(runs, func() { result = countPrimes(*limit) })
+	benchlib.ReportRuns("primes", test, result, samples)
+	if *limit == defaultLimit && result != primesBelowDefault {
+		benchlib.ReportErr("primes", test, primesBelowDefault, result)
+	}
+
+	// sieve-<limit>, checked against the trial-division count
+	trial := result
+	test = "sieve-" + benchlib.SizeName(*limit)
+	samples = benchlib.RunN(runs, func() { result = countPrimesSieve(*limit) })
+	benchlib.ReportRuns("primes", test, result, samples)
+	if result != trial {
+		benchlib.ReportErr("primes", test, trial, result)
+	}
+
+	// max-gap-1m
+	test = "max-gap-" + benchlib.SizeName(gapLimit)
+	samples = benchlib.RunN(runs, func() { result = maxPrimeGap(gapLimit) })
+	benchlib.ReportRuns("primes", test, result, samples)
+	if result != maxGapBelowGapLimit {
+		benchlib.ReportErr("primes", test, maxGapBelowGapLimit, result)
+	}
+
+	// nth-100k
+	test = "nth-" + benchlib.SizeName(nthIndex)
+	samples = benchlib.RunN(runs, func() { result = nthPrime(nthIndex) })
+	benchlib.ReportRuns("primes", test, result, samples)
+	if result != nthPrimeExpected {
+		benchlib.ReportErr("primes", test, nthPrimeExpected, result)
+	}
+}
@@ -0,0 +1,102 @@
+package compute
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultPiDigits = 1000
+	// piDigitSum1000 is the sum of the first 1000 decimal digits of pi,
+	// counting the leading 3.
+	piDigitSum1000 = 4470
+	// spigotGuard is how many digits past the requested count are
+	// generated, so a run of 9s still being held back at the end can't
+	// leave the last requested digits unsettled.
+	spigotGuard = 10
+)
+
+// piDigits returns the first n decimal digits of pi (3, 1, 4, ...) using
+// the Rabinowitz-Wagon spigot, which needs only integer arithmetic. Each
+// pass yields a predigit that may still grow by one, so digits are held
+// back until the next pass shows they can't carry.
+func piDigits(n int) []byte {
+	total := n + spigotGuard
+	size := total*10/3 + 1
+	a := make([]int64, size)
+	for i := range a {
+		a[i] = 2
+	}
+
+	// out starts with the placeholder predigit 0 that precedes the 3
+	out := make([]byte, 0, total+1)
+	var predigit byte
+	nines := 0
+	for j := 0; j < total; j++ {
+		var q int64
+		for i := int64(size); i > 0; i-- {
+			x := 10*a[i-1] + q*i
+			a[i-1] = x % (2*i - 1)
+			q = x / (2*i - 1)
+		}
+		a[0] = q % 10
+		q /= 10
+
+		switch q {
+		case 9:
+			nines++
+		case 10:
+			out = append(out, predigit+1)
+			for ; nines > 0; nines-- {
+				out = append(out, 0)
+			}
+			predigit = 0
+		default:
+			out = append(out, predigit)
+			for ; nines > 0; nines-- {
+				out = append(out, 9)
+			}
+			predigit = byte(q)
+		}
+	}
+	return out[1 : n+1]
+}
+
+func init() {
+	benchlib.Register("spigot", runSpigot, "cpu")
+}
+
+// runSpigot runs the pi spigot benchmark.
+// Output format: BENCH:spigot:<test>:<result>:<time_ms>:<time_ns>
+//
+// Computes the first -n decimal digits of pi (1000 by default) with the
+// Rabinowitz-Wagon spigot, an exact integer-only alternative to
+// leibniz_pi. The result is the sum of the digits, which must be 4470 for
+// the default n.
+func runSpigot(args []string) {
+	fs := flag.NewFlagSet("spigot", flag.ExitOnError)
+	n := fs.Int("n", defaultPiDigits, "number of digits of pi to compute")
+	fs.Parse(args)
+	if *n < 1 {
+		benchlib.Usagef("spigot", "-n must be at least 1, got %d", *n)
+		return
+	}
+	benchlib.ReportEnv()
+
+	test := fmt.Sprintf("pi-digits-%d", *n)
+	start := time.Now()
+	digits := piDigits(*n)
+	elapsed := time.Since(start)
+
+	var sum int64
+	for _, d := range digits {
+		sum += int64(d)
+	}
+	benchlib.Report("spigot", test, sum, elapsed)
+	if *n == defaultPiDigits && sum != piDigitSum1000 {
+		benchlib.ReportErr("spigot", test, piDigitSum1000, sum)
+	}
+}
@@ -0,0 +1,118 @@
+package compute
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const defaultSumN = 1000000
+
+var errOverflow = errors.New("int64 overflow")
+
+// sumSquares sums the squares of 1..n, failing with errOverflow as soon as
+// a square or the running total no longer fits in an int64.
+func sumSquares(n int64) (int64, error) {
+	var acc int64
+	for i := int64(1); i <= n; i++ {
+		if i > math.MaxInt64/i {
+			return 0, fmt.Errorf("%w squaring %d", errOverflow, i)
+		}
+		sq := i * i
+		if acc > math.MaxInt64-sq {
+			return 0, fmt.Errorf("%w adding %d²", errOverflow, i)
+		}
+		acc += sq
+	}
+	return acc, nil
+}
+
+func sumSquaresBig(n int64) *big.Int {
+	acc := new(big.Int)
+	sq := new(big.Int)
+	for i := int64(1); i <= n; i++ {
+		sq.SetInt64(i)
+		sq.Mul(sq, sq)
+		acc.Add(acc, sq)
+	}
+	return acc
+}
+
+// sumSquaresExpected returns n(n+1)(2n+1)/6, the closed form of the sum.
+func sumSquaresExpected(n int64) *big.Int {
+	bn := big.NewInt(n)
+	e := new(big.Int).Add(bn, big.NewInt(1))
+	e.Mul(e, bn)
+	e.Mul(e, new(big.Int).Add(new(big.Int).Lsh(bn, 1), big.NewInt(1)))
+	return e.Div(e, big.NewInt(6))
+}
+
+// timeMin returns the time of one call of f, averaged over enough calls
+// to fill minTime.
+func timeMin(minTime time.Duration, f func()) time.Duration {
+	return benchlib.RunMinTime(1, minTime, f)[0]
+}
+
+func init() {
+	benchlib.Register("sum_squares", runSumSquares, "cpu")
+}
+
+// runSumSquares runs the sum-of-squares benchmark.
+// Output format: BENCH:sum_squares:<test>:<result>:<time_ms>:<time_ns>
+//
+// Sums 1² + 2² + ... + n² (-n, default 1,000,000) in an int64 accumulator.
+// The sum overflows int64 for n beyond about 3 million; rather than report
+// a wrapped value, the loop detects the overflow and the run fails. With
+// -big the sum is accumulated in a math/big.Int instead and reported as
+// sum-squares-big-<n>, whose result field holds the low 63 bits when the
+// sum doesn't fit; outside JSON mode the full value is printed on its own
+// line. Either loop is repeated until it has run for -min-time and the
+// per-pass time is reported, since a single pass at the default n takes
+// about a millisecond.
+func runSumSquares(args []string) {
+	fs := flag.NewFlagSet("sum_squares", flag.ExitOnError)
+	n := fs.Int64("n", defaultSumN, "sum the squares of 1..n")
+	useBig := fs.Bool("big", false, "accumulate in a math/big.Int so large n can't overflow")
+	minTime := fs.Duration("min-time", benchlib.DefaultMinTime, "repeat the sum until it has run for at least this long")
+	fs.Parse(args)
+	if *n < 1 {
+		benchlib.Usagef("sum_squares", "-n must be at least 1, got %d", *n)
+		return
+	}
+	benchlib.ReportEnv()
+
+	want := sumSquaresExpected(*n)
+
+	if *useBig {
+		test := "sum-squares-big-" + benchlib.SizeName(*n)
+		var sum *big.Int
+		elapsed := timeMin(*minTime, func() { sum = sumSquaresBig(*n) })
+
+		benchlib.Notef("sum = %s", sum)
+		low := new(big.Int).And(sum, big.NewInt(math.MaxInt64)).Int64()
+		benchlib.Report("sum_squares", test, low, elapsed)
+		if sum.Cmp(want) != 0 {
+			benchlib.Failf("sum_squares", test, "expected %s, got %s", want, sum)
+		}
+		return
+	}
+
+	test := "sum-squares-" + benchlib.SizeName(*n)
+	var sum int64
+	var err error
+	elapsed := timeMin(*minTime, func() { sum, err = sumSquares(*n) })
+
+	if err != nil {
+		benchlib.Failf("sum_squares", test, "%v (rerun with -big)", err)
+		return
+	}
+	benchlib.Report("sum_squares", test, sum, elapsed)
+	if !want.IsInt64() || sum != want.Int64() {
+		benchlib.Failf("sum_squares", test, "expected %s, got %d", want, sum)
+	}
+}
@@ -0,0 +1,97 @@
+package compute
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	corpusWords = 200000
+	// wordLetters is the alphabet corpus words are drawn from. It is small
+	// enough that short words repeat, so the map sees updates as well as
+	// insertions, while long words are mostly unique.
+	wordLetters = "etaoinshrdlucmfw"
+	// maxWordLen bounds corpus word length; lengths are 1..maxWordLen.
+	maxWordLen = 5
+	// wordsPerLine is how many words share a line of the corpus.
+	wordsPerLine = 12
+	// expectedDistinct is the distinct-word count for the default-seed
+	// corpus.
+	expectedDistinct = 73428
+)
+
+// wordCorpus returns n words separated by spaces, with a newline after
+// every wordsPerLine words. Each word takes its length from
+// 1 + (next>>33)%maxWordLen and each letter from wordLetters[(next>>33)%16],
+// so other runtimes can regenerate it exactly.
+func wordCorpus(n int, seed uint64) string {
+	rng := benchlib.NewLCG(seed)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			if i%wordsPerLine == 0 {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		length := 1 + int((rng.Next()>>33)%maxWordLen)
+		for j := 0; j < length; j++ {
+			b.WriteByte(wordLetters[(rng.Next()>>33)%uint64(len(wordLetters))])
+		}
+	}
+	return b.String()
+}
+
+// countWords tallies how often each word occurs.
+func countWords(words []string) map[string]int {
+	counts := make(map[string]int)
+	for _, w := range words {
+		counts[w]++
+	}
+	return counts
+}
+
+func init() {
+	benchlib.Register("wordcount", runWordcount, "cpu", "gc")
+}
+
+// runWordcount runs the word-count benchmark.
+// Output format: BENCH:wordcount:<test>:<result>:<time_ms>:<time_ns>
+//
+// Splits a generated corpus of 200,000 words into tokens with
+// strings.Fields, then counts each word's occurrences in a map. The two
+// phases are timed separately: tokenize-200k reports the token count and
+// count-200k the number of distinct words. The corpus is built outside
+// the timed sections.
+func runWordcount(args []string) {
+	fs := flag.NewFlagSet("wordcount", flag.ExitOnError)
+	fs.Parse(args)
+	benchlib.ReportEnv()
+
+	text := wordCorpus(corpusWords, benchlib.Seed())
+	size := benchlib.SizeName(corpusWords)
+
+	test := "tokenize-" + size
+	start := time.Now()
+	words := strings.Fields(text)
+	elapsed := time.Since(start)
+	benchlib.Report("wordcount", test, int64(len(words)), elapsed)
+	if len(words) != corpusWords {
+		benchlib.ReportErr("wordcount", test, corpusWords, int64(len(words)))
+		return
+	}
+
+	test = "count-" + size
+	start = time.Now()
+	counts := countWords(words)
+	elapsed = time.Since(start)
+	distinct := int64(len(counts))
+	benchlib.Report("wordcount", test, distinct, elapsed)
+	if benchlib.Seed() == benchlib.DefaultSeed && distinct != expectedDistinct {
+		benchlib.ReportErr("wordcount", test, expectedDistinct, distinct)
+	}
+}
@@ -0,0 +1,91 @@
+// Package fanout holds the producer and worker structure of the fanout
+// benchmark so the fanout and fanout_sweep mains can share it.
+package fanout
+
+import (
+	"context"
+	"runtime"
+)
+
+// sentinel tells a worker to report its count and stop. Workers drain
+// every message ahead of it, which closing the channel would not
+// guarantee across several receivers.
+const sentinel = -1
+
+// Pool is one producer feeding a fixed set of workers over a shared
+// channel.
+type Pool struct {
+	work    chan int
+	done    chan int
+	workers int
+}
+
+// NewPool starts workers goroutines receiving from a work channel of
+// capacity buffer. With yield, workers call runtime.Gosched after every
+// message.
+func NewPool(workers, buffer int, yield bool) *Pool {
+	p := &Pool{
+		work:    make(chan int, buffer),
+		done:    make(chan int, workers),
+		workers: workers,
+	}
+	for i := 0; i < workers; i++ {
+		go worker(p.work, p.done, yield)
+	}
+	return p
+}
+
+// Run sends messages values followed by one sentinel per worker, and
+// returns the number of messages the workers received between them. The
+// workers have exited when it returns, so a Pool runs once.
+func (p *Pool) Run(messages int) int {
+	for i := 0; i < messages; i++ {
+		p.work <- i
+	}
+	for i := 0; i < p.workers; i++ {
+		p.work <- sentinel
+	}
+	total := 0
+	for i := 0; i < p.workers; i++ {
+		total += <-p.done
+	}
+	return total
+}
+
+// RunFor sends messages until ctx is done, then closes the work channel.
+// The workers drain whatever is still buffered before they stop, so every
+// message sent is received. It returns the number sent and the number the
+// workers received, which must match. Like Run, a Pool runs once.
+func (p *Pool) RunFor(ctx context.Context) (sent, received int) {
+	done := ctx.Done()
+produce:
+	for {
+		select {
+		case <-done:
+			break produce
+		case p.work <- sent:
+			sent++
+		}
+	}
+	close(p.work)
+	for i := 0; i < p.workers; i++ {
+		received += <-p.done
+	}
+	return sent, received
+}
+
+// worker counts messages until it receives a sentinel or the work channel
+// is closed, then reports its count.
+func worker(workChan <-chan int, doneChan chan<- int, yield bool) {
+	count := 0
+	for val := range workChan {
+		if val == sentinel {
+			break
+		}
+		count++
+		if yield {
+			runtime.Gosched()
+		}
+	}
+	doneChan <- count
+}
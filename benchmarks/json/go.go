@@ -0,0 +1,115 @@
+// JSON Benchmark - Go implementation
+// Output format: BENCH:json:<test>:<result>:<time_ms>:<time_ns>
+//
+// Marshals a fixed slice of 1,000 records to JSON and unmarshals it back,
+// -n times each (100 by default), timing the two phases separately. The
+// records are generated from the shared LCG (seed from BENCH_SEED).
+// encode reports the encoded size in bytes; decode reports a checksum over
+// the decoded records, which must match the checksum of the originals.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const numRecords = 1000
+
+type record struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Score  float64  `json:"score"`
+	Tags   []string `json:"tags"`
+	Active bool     `json:"active"`
+}
+
+var tagPool = []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta"}
+
+func newRecords(n int, seed uint64) []record {
+	rng := benchlib.NewLCG(seed)
+	next := func(bound uint64) uint64 { return (rng.Next() >> 33) % bound }
+	recs := make([]record, n)
+	for i := range recs {
+		tags := make([]string, next(4))
+		for j := range tags {
+			tags[j] = tagPool[next(uint64(len(tagPool)))]
+		}
+		recs[i] = record{
+			ID:     int64(next(1 << 30)),
+			Name:   fmt.Sprintf("user-%d", i),
+			Score:  float64(next(100000)) / 100,
+			Tags:   tags,
+			Active: next(2) == 1,
+		}
+	}
+	return recs
+}
+
+// checksum folds every field of recs into one value, so a field lost or
+// altered in the round trip changes it.
+func checksum(recs []record) int64 {
+	var sum int64
+	for _, r := range recs {
+		sum += r.ID + int64(math.Round(r.Score*100)) + int64(len(r.Name))
+		for _, t := range r.Tags {
+			sum += int64(len(t))
+		}
+		if r.Active {
+			sum++
+		}
+	}
+	return sum
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "gc")
+
+	n := flag.Int("n", 100, "number of encode and decode passes")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "json: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	recs := newRecords(numRecords, benchlib.Seed())
+	suffix := fmt.Sprintf("-%s-x%d", benchlib.SizeName(numRecords), *n)
+
+	var data []byte
+	start := time.Now()
+	for i := 0; i < *n; i++ {
+		var err error
+		data, err = json.Marshal(recs)
+		if err != nil {
+			benchlib.Failf("json", "encode"+suffix, "%v", err)
+			benchlib.Exit(1)
+		}
+	}
+	elapsed := time.Since(start)
+	benchlib.Report("json", "encode"+suffix, int64(len(data)), elapsed)
+
+	var decoded []record
+	start = time.Now()
+	for i := 0; i < *n; i++ {
+		decoded = nil
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			benchlib.Failf("json", "decode"+suffix, "%v", err)
+			benchlib.Exit(1)
+		}
+	}
+	elapsed = time.Since(start)
+
+	got := checksum(decoded)
+	benchlib.Report("json", "decode"+suffix, got, elapsed)
+	if want := checksum(recs); got != want {
+		benchlib.ReportErr("json", "decode"+suffix, want, got)
+		benchlib.Exit(1)
+	}
+}
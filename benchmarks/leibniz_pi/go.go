@@ -0,0 +1,19 @@
+// Leibniz Pi Benchmark - Go implementation
+// Output format: BENCH:leibniz_pi:<test>:<result>:<time_ms>:<time_ns>
+//
+// The benchmark lives in internal/compute, registered as "leibniz_pi", so the
+// combined compute binary can run it too. This main runs it alone.
+package main
+
+import (
+	"os"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+	_ "github.com/navicore/patch-seq/benchmarks/internal/compute"
+)
+
+func main() {
+	defer benchlib.Finish()
+
+	benchlib.Run("leibniz_pi", os.Args[1:])
+}
@@ -0,0 +1,140 @@
+// Linsolve Benchmark - Go implementation
+// Output format: BENCH:linsolve:<test>:<result>:<time_ms>:<time_ns>
+//
+// Solves a dense NxN system Ax = b (-n, default 128) by Gaussian
+// elimination with partial pivoting. A's entries come from the shared LCG
+// (seed from BENCH_SEED) scaled into [-1, 1), and b is A times the vector
+// 1, 2, ..., n, so the exact solution is known. The result is the sum of
+// the solution vector scaled by 1000 and rounded (n(n+1)/2 * 1000 when
+// solved accurately), and the relative residual ||Ax-b|| / ||b|| must be
+// below 1e-10.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultN = 128
+	// maxResidual bounds the relative residual of an accurate solve.
+	maxResidual = 1e-10
+	// sumScale converts the solution sum to the integer result field.
+	sumScale = 1000
+)
+
+// newSystem returns A and b for an n-unknown system whose solution is
+// x[i] = i+1.
+func newSystem(n int, seed uint64) (a [][]float64, b []float64) {
+	rng := benchlib.NewLCG(seed)
+	a = make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n)
+		for j := range a[i] {
+			// 31 high bits mapped onto [-1, 1)
+			a[i][j] = float64(rng.Next()>>33)/(1<<30) - 1
+		}
+	}
+	b = make([]float64, n)
+	for i := range b {
+		for j := range a[i] {
+			b[i] += a[i][j] * float64(j+1)
+		}
+	}
+	return a, b
+}
+
+// solve overwrites a and b while solving ax = b, returning x. It reports
+// false if a is singular to working precision.
+func solve(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(a)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if a[pivot][col] == 0 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		for j := i + 1; j < n; j++ {
+			sum -= a[i][j] * x[j]
+		}
+		x[i] = sum / a[i][i]
+	}
+	return x, true
+}
+
+// residual returns ||ax - b|| / ||b|| in the Euclidean norm.
+func residual(a [][]float64, x, b []float64) float64 {
+	var rr, bb float64
+	for i := range a {
+		r := -b[i]
+		for j := range a[i] {
+			r += a[i][j] * x[j]
+		}
+		rr += r * r
+		bb += b[i] * b[i]
+	}
+	return math.Sqrt(rr / bb)
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "float")
+
+	n := flag.Int("n", defaultN, "number of unknowns")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "linsolve: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	a, b := newSystem(*n, benchlib.Seed())
+	// solve works in place; keep the originals for the residual check
+	work := make([][]float64, *n)
+	for i := range a {
+		work[i] = append([]float64(nil), a[i]...)
+	}
+	rhs := append([]float64(nil), b...)
+
+	test := fmt.Sprintf("gauss-%d", *n)
+	start := time.Now()
+	x, ok := solve(work, rhs)
+	elapsed := time.Since(start)
+	if !ok {
+		benchlib.Failf("linsolve", test, "matrix is singular")
+		return
+	}
+
+	var sum float64
+	for _, v := range x {
+		sum += v
+	}
+	benchlib.Report("linsolve", test, int64(math.Round(sum*sumScale)), elapsed)
+	if r := residual(a, x, b); !(r < maxResidual) {
+		benchlib.Failf("linsolve", test, "relative residual %.3e exceeds %.0e", r, maxResidual)
+	}
+}
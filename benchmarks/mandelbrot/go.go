@@ -0,0 +1,75 @@
+// Mandelbrot Benchmark - Go implementation
+// Output format: BENCH:mandelbrot:<test>:<result>:<time_ms>:<time_ns>
+// Build: cd mandelbrot && go build -o mandelbrot_go go.go
+//
+// Computes escape-time iteration counts over a fixed 800x600 grid of the
+// complex plane and reports the total number of iterations. A pure float64
+// workload with a branch-heavy inner loop.
+package main
+
+import (
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	width         = 800
+	height        = 600
+	maxIterations = 1000
+
+	xMin, xMax = -2.0, 1.0
+	yMin, yMax = -1.2, 1.2
+
+	// expectedIterations is the total for the grid above.
+	expectedIterations = 103502439
+)
+
+// escape returns the number of iterations before z = z^2 + c leaves the
+// radius-2 disc, capped at maxIterations. The explicit float64 conversions
+// stop the compiler fusing multiply-adds on architectures with FMA, which
+// would change the rounding and the count.
+func escape(cr, ci float64) int64 {
+	var zr, zi float64
+	var n int64
+	for n < maxIterations {
+		zr2 := float64(zr * zr)
+		zi2 := float64(zi * zi)
+		if zr2+zi2 > 4.0 {
+			break
+		}
+		zi = float64(2*zr*zi) + ci
+		zr = zr2 - zi2 + cr
+		n++
+	}
+	return n
+}
+
+func mandelbrot() int64 {
+	var total int64
+	for py := 0; py < height; py++ {
+		ci := yMin + float64(py)*(yMax-yMin)/height
+		for px := 0; px < width; px++ {
+			cr := xMin + float64(px)*(xMax-xMin)/width
+			total += escape(cr, ci)
+		}
+	}
+	return total
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "float")
+
+	benchlib.ReportEnv()
+
+	start := time.Now()
+	total := mandelbrot()
+	elapsed := time.Since(start)
+
+	benchlib.Report("mandelbrot", "escape-800x600", total, elapsed)
+	if total != expectedIterations {
+		benchlib.ReportErr("mandelbrot", "escape-800x600", expectedIterations, total)
+		benchlib.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+// Matmul Benchmark - Go implementation
+// Output format: BENCH:matmul:<test>:<result>:<time_ms>:<time_ns>
+//
+// Multiplies two NxN int64 matrices with the textbook i-j-k loop order,
+// stressing nested loops and cache locality. The result is the sum of
+// every entry of the product matrix.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	defaultN = 256
+	// checksumDefault is the product checksum for defaultN.
+	checksumDefault = 18013940382433280
+)
+
+// newMatrices returns deterministic inputs: a[i][j] = i*n+j and b is its
+// transpose, so any runtime can reproduce them exactly.
+func newMatrices(n int) (a, b [][]int64) {
+	a = make([][]int64, n)
+	b = make([][]int64, n)
+	for i := 0; i < n; i++ {
+		a[i] = make([]int64, n)
+		b[i] = make([]int64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a[i][j] = int64(i*n + j)
+			b[j][i] = int64(i*n + j)
+		}
+	}
+	return a, b
+}
+
+func multiply(a, b [][]int64) [][]int64 {
+	n := len(a)
+	c := make([][]int64, n)
+	for i := 0; i < n; i++ {
+		c[i] = make([]int64, n)
+		for j := 0; j < n; j++ {
+			var sum int64
+			for k := 0; k < n; k++ {
+				sum += a[i][k] * b[k][j]
+			}
+			c[i][j] = sum
+		}
+	}
+	return c
+}
+
+func checksum(m [][]int64) int64 {
+	var sum int64
+	for _, row := range m {
+		for _, v := range row {
+			sum += v
+		}
+	}
+	return sum
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "float")
+
+	n := flag.Int("n", defaultN, "matrix dimension")
+	flag.Parse()
+	benchlib.ReportEnv()
+
+	a, b := newMatrices(*n)
+
+	start := time.Now()
+	c := multiply(a, b)
+	elapsed := time.Since(start)
+
+	test := fmt.Sprintf("multiply-%d", *n)
+	result := checksum(c)
+	benchlib.Report("matmul", test, result, elapsed)
+	if *n == defaultN && result != checksumDefault {
+		benchlib.ReportErr("matmul", test, checksumDefault, result)
+		benchlib.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+// N-body Benchmark - Go implementation
+// Output format: BENCH:nbody:<test>:<result>:<time_ms>:<time_ns>
+//
+// The benchmarks game 5-body simulation (Sun, Jupiter, Saturn, Uranus,
+// Neptune) with its standard initial conditions. Prints the final energy
+// to 9 decimal places, and reports it in the result field scaled by 1e9.
+package main
+
+import (
+	"flag"
+	"math"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	solarMass   = 4 * math.Pi * math.Pi
+	daysPerYear = 365.24
+	dt          = 0.01
+)
+
+// knownEnergy is the published final energy for common step counts.
+var knownEnergy = map[int]float64{
+	0:        -0.169075164,
+	1000:     -0.169087605,
+	1000000:  -0.169086185,
+	50000000: -0.169059907,
+}
+
+type body struct {
+	x, y, z, vx, vy, vz, mass float64
+}
+
+func newSystem() []body {
+	bodies := []body{
+		// Sun
+		{mass: solarMass},
+		// Jupiter
+		{
+			x: 4.84143144246472090e+00, y: -1.16032004402742839e+00, z: -1.03622044471123109e-01,
+			vx: 1.66007664274403694e-03 * daysPerYear, vy: 7.69901118419740425e-03 * daysPerYear, vz: -6.90460016972063023e-05 * daysPerYear,
+			mass: 9.54791938424326609e-04 * solarMass,
+		},
+		// Saturn
+		{
+			x: 8.34336671824457987e+00, y: 4.12479856412430479e+00, z: -4.03523417114321381e-01,
+			vx: -2.76742510726862411e-03 * daysPerYear, vy: 4.99852801234917238e-03 * daysPerYear, vz: 2.30417297573763929e-05 * daysPerYear,
+			mass: 2.85885980666130812e-04 * solarMass,
+		},
+		// Uranus
+		{
+			x: 1.28943695621391310e+01, y: -1.51111514016986312e+01, z: -2.23307578892655734e-01,
+			vx: 2.96460137564761618e-03 * daysPerYear, vy: 2.37847173959480950e-03 * daysPerYear, vz: -2.96589568540237556e-05 * daysPerYear,
+			mass: 4.36624404335156298e-05 * solarMass,
+		},
+		// Neptune
+		{
+			x: 1.53796971148509165e+01, y: -2.59193146099879641e+01, z: 1.79258772950371181e-01,
+			vx: 2.68067772490389322e-03 * daysPerYear, vy: 1.62824170038242295e-03 * daysPerYear, vz: -9.51592254519715870e-05 * daysPerYear,
+			mass: 5.15138902046611451e-05 * solarMass,
+		},
+	}
+
+	// Offset the Sun's momentum so the system's total momentum is zero
+	var px, py, pz float64
+	for _, b := range bodies {
+		px += b.vx * b.mass
+		py += b.vy * b.mass
+		pz += b.vz * b.mass
+	}
+	bodies[0].vx = -px / solarMass
+	bodies[0].vy = -py / solarMass
+	bodies[0].vz = -pz / solarMass
+	return bodies
+}
+
+func advance(bodies []body) {
+	for i := range bodies {
+		bi := &bodies[i]
+		for j := i + 1; j < len(bodies); j++ {
+			bj := &bodies[j]
+			dx := bi.x - bj.x
+			dy := bi.y - bj.y
+			dz := bi.z - bj.z
+			dSquared := dx*dx + dy*dy + dz*dz
+			distance := math.Sqrt(dSquared)
+			mag := dt / (dSquared * distance)
+			bi.vx -= dx * bj.mass * mag
+			bi.vy -= dy * bj.mass * mag
+			bi.vz -= dz * bj.mass * mag
+			bj.vx += dx * bi.mass * mag
+			bj.vy += dy * bi.mass * mag
+			bj.vz += dz * bi.mass * mag
+		}
+	}
+	for i := range bodies {
+		b := &bodies[i]
+		b.x += dt * b.vx
+		b.y += dt * b.vy
+		b.z += dt * b.vz
+	}
+}
+
+func energy(bodies []body) float64 {
+	var e float64
+	for i, bi := range bodies {
+		e += 0.5 * bi.mass * (bi.vx*bi.vx + bi.vy*bi.vy + bi.vz*bi.vz)
+		for _, bj := range bodies[i+1:] {
+			dx := bi.x - bj.x
+			dy := bi.y - bj.y
+			dz := bi.z - bj.z
+			e -= bi.mass * bj.mass / math.Sqrt(dx*dx+dy*dy+dz*dz)
+		}
+	}
+	return e
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "float")
+
+	steps := flag.Int("steps", 1000000, "number of simulation steps")
+	flag.Parse()
+	benchlib.ReportEnv()
+
+	bodies := newSystem()
+
+	start := time.Now()
+	for i := 0; i < *steps; i++ {
+		advance(bodies)
+	}
+	elapsed := time.Since(start)
+
+	e := energy(bodies)
+	benchlib.Notef("%.9f", e)
+
+	test := "energy-" + benchlib.SizeName(int64(*steps))
+	benchlib.Report("nbody", test, int64(math.Round(e*1e9)), elapsed)
+	if want, ok := knownEnergy[*steps]; ok && math.Abs(e-want) > 1e-8 {
+		benchlib.Failf("nbody", test, "energy %.9f deviates from %.9f", e, want)
+		benchlib.Exit(1)
+	}
+}
@@ -0,0 +1,94 @@
+// Philosophers Benchmark - Go implementation
+// Output format: BENCH:philosophers:<test>:<result>:<time_ms>:<time_ns>
+//
+// Dining philosophers: -philosophers goroutines (5 by default) sit at a
+// round table with one mutex "fork" between each pair of neighbours, and
+// each must hold both of its forks to eat -meals times (1,000,000 by default).
+// Every philosopher picks up the lower-numbered of its two forks first; that
+// fixed resource ordering means no cycle of waits can form, so the table
+// never deadlocks. The result is the total meals eaten, which must equal
+// philosophers x meals, and a -throughput line reports meals per second.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// dine seats philosophers at a table and returns the meals each ate.
+func dine(philosophers int, mealsPer int64) []int64 {
+	forks := make([]sync.Mutex, philosophers)
+	eaten := make([]int64, philosophers)
+	var wg sync.WaitGroup
+	wg.Add(philosophers)
+	for p := 0; p < philosophers; p++ {
+		first, second := p, (p+1)%philosophers
+		if second < first {
+			first, second = second, first
+		}
+		go func(p, first, second int) {
+			defer wg.Done()
+			for i := int64(0); i < mealsPer; i++ {
+				forks[first].Lock()
+				forks[second].Lock()
+				eaten[p]++
+				forks[second].Unlock()
+				forks[first].Unlock()
+			}
+		}(p, first, second)
+	}
+	wg.Wait()
+	return eaten
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	philosophers := flag.Int("philosophers", 5, "number of philosophers (and forks) at the table")
+	mealsPer := flag.Int64("meals", 1000000, "meals each philosopher eats")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *philosophers < 2 {
+		// a lone philosopher's left and right fork are the same mutex
+		fmt.Fprintf(os.Stderr, "philosophers: -philosophers must be at least 2, got %d\n", *philosophers)
+		os.Exit(2)
+	}
+	if *mealsPer < 1 {
+		fmt.Fprintf(os.Stderr, "philosophers: -meals must be at least 1, got %d\n", *mealsPer)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	eaten := dine(*philosophers, *mealsPer)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	test := fmt.Sprintf("ordered-%d", *philosophers)
+	var total int64
+	for p, meals := range eaten {
+		total += meals
+		if meals != *mealsPer {
+			benchlib.Failf("philosophers", test, "philosopher %d ate %d meals, want %d", p, meals, *mealsPer)
+		}
+	}
+	expected := int64(*philosophers) * *mealsPer
+	benchlib.ReportWithCPU("philosophers", test, total, elapsed, cpuTime)
+	benchlib.ReportRate("philosophers", test, total, "meals", elapsed)
+	if total != expected {
+		benchlib.ReportErr("philosophers", test, expected, total)
+	}
+	leaks.Verify("philosophers")
+}
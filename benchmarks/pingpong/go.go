@@ -1,13 +1,23 @@
 // Pingpong Benchmark - Go implementation
-// Output format: BENCH:pingpong:<test>:<result>:<time_ms>
+// Output format: BENCH:pingpong:<test>:<result>:<time_ms>:<time_ns>
 //
 // Two goroutines exchange messages N times.
-// Tests channel round-trip latency.
+// Tests channel round-trip latency. The result is the sum of the values
+// that came back, which catches dropped or corrupted messages.
+//
+// With -latency, a second pass times every round trip individually into a
+// histogram and reports roundtrip-100k-p50, -p90, -p99 and -p99.9 in
+// nanoseconds. Each sample includes one clock read (tens of nanoseconds
+// on Linux), which is a noticeable share of an unbuffered round trip, so
+// the percentiles overstate latency slightly and the pass is kept out of
+// the default throughput measurement.
 package main
 
 import (
-	"fmt"
+	"flag"
 	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
 )
 
 const iterations = 100000
@@ -19,23 +29,72 @@ func pong(pingChan, pongChan chan int, count int) {
 	}
 }
 
-func ping(pingChan, pongChan chan int, count int) {
+// ping sends 0..count-1 and returns the sum of the echoed replies.
+func ping(pingChan, pongChan chan int, count int) int64 {
+	var sum int64
+	for i := 0; i < count; i++ {
+		pingChan <- i
+		sum += int64(<-pongChan)
+	}
+	return sum
+}
+
+// pingTimed is ping with each round trip timed into h.
+func pingTimed(pingChan, pongChan chan int, count int, h *benchlib.Histogram) int64 {
+	var sum int64
 	for i := 0; i < count; i++ {
+		start := time.Now()
 		pingChan <- i
-		<-pongChan
+		sum += int64(<-pongChan)
+		h.Record(time.Since(start))
 	}
+	return sum
 }
 
 func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	latency := flag.Bool("latency", false, "also time each round trip and report latency percentiles")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
 	pingChan := make(chan int)
 	pongChan := make(chan int)
 
+	cpu := benchlib.StartCPU()
 	start := time.Now()
 
 	go pong(pingChan, pongChan, iterations)
-	ping(pingChan, pongChan, iterations)
+	sum := ping(pingChan, pongChan, iterations)
 
-	elapsed := time.Since(start).Milliseconds()
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
 
-	fmt.Printf("BENCH:pingpong:roundtrip-100k:%d:%d\n", iterations, elapsed)
+	benchlib.ReportWithCPU("pingpong", "roundtrip-100k", sum, elapsed, cpuTime)
+	// Each round trip is two messages
+	benchlib.ReportThroughput("pingpong", "roundtrip-100k", 2*iterations, elapsed)
+	expected := int64(iterations) * (iterations - 1) / 2
+	if sum != expected {
+		benchlib.ReportErr("pingpong", "roundtrip-100k", expected, sum)
+		benchlib.Exit(1)
+	}
+
+	if *latency {
+		var h benchlib.Histogram
+		go pong(pingChan, pongChan, iterations)
+		sum = pingTimed(pingChan, pongChan, iterations, &h)
+		benchlib.ReportLatency("pingpong", "roundtrip-100k", &h)
+		if sum != expected {
+			benchlib.ReportErr("pingpong", "roundtrip-100k-latency", expected, sum)
+			benchlib.Exit(1)
+		}
+	}
+	leaks.Verify("pingpong")
 }
@@ -0,0 +1,91 @@
+// Pipeline Benchmark - Go implementation
+// Output format: BENCH:pipeline:<test>:<result>:<time_ms>:<time_ns>
+//
+// Streams numElements values through three goroutine stages joined by
+// channels: generate -> square -> filter-even, with main summing what
+// comes out. The result is that sum, so dropped or duplicated values
+// show up as a checksum mismatch.
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const numElements = 100000
+const bufferSize = 100
+
+func generate(n int64) <-chan int64 {
+	out := make(chan int64, bufferSize)
+	go func() {
+		for i := int64(0); i < n; i++ {
+			out <- i
+		}
+		close(out)
+	}()
+	return out
+}
+
+func square(in <-chan int64) <-chan int64 {
+	out := make(chan int64, bufferSize)
+	go func() {
+		for v := range in {
+			out <- v * v
+		}
+		close(out)
+	}()
+	return out
+}
+
+func filterEven(in <-chan int64) <-chan int64 {
+	out := make(chan int64, bufferSize)
+	go func() {
+		for v := range in {
+			if v%2 == 0 {
+				out <- v
+			}
+		}
+		close(out)
+	}()
+	return out
+}
+
+// expectedSum is the sum of the even squares below n^2: the squares of
+// 0, 2, 4, ..., which is 4 * sum(k^2) for k below ceil(n/2).
+func expectedSum(n int64) int64 {
+	m := (n + 1) / 2
+	return 4 * (m - 1) * m * (2*m - 1) / 6
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	var sum int64
+	for v := range filterEven(square(generate(numElements))) {
+		sum += v
+	}
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	benchlib.ReportWithCPU("pipeline", "3stage-100k", sum, elapsed, cpuTime)
+	benchlib.ReportThroughput("pipeline", "3stage-100k", numElements, elapsed)
+	if want := expectedSum(numElements); sum != want {
+		benchlib.ReportErr("pipeline", "3stage-100k", want, sum)
+		benchlib.Exit(1)
+	}
+	leaks.Verify("pipeline")
+}
@@ -0,0 +1,52 @@
+// Regex Benchmark - Go implementation
+// Output format: BENCH:regex:<test>:<result>:<time_ms>:<time_ns>
+//
+// Counts non-overlapping matches of a fixed pattern across 1MB of
+// pseudo-random lowercase text. The corpus comes from the shared LCG
+// (seed from BENCH_SEED, default 1), taking letter 'a' + (next>>33)%26
+// for each byte, so other runtimes can regenerate it exactly. The pattern
+// is compiled once, outside the timed section.
+package main
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const (
+	corpusSize = 1 << 20
+	pattern    = `[aeiou][b-df-hj-np-tv-z]{2}[aeiou]|q[a-z]?u+`
+	// expectedMatches is the match count for the default-seed corpus.
+	expectedMatches = 24710
+)
+
+func corpus(n int, seed uint64) []byte {
+	rng := benchlib.NewLCG(seed)
+	text := make([]byte, n)
+	for i := range text {
+		text[i] = 'a' + byte((rng.Next()>>33)%26)
+	}
+	return text
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	benchlib.ReportEnv()
+
+	text := corpus(corpusSize, benchlib.Seed())
+	re := regexp.MustCompile(pattern)
+
+	start := time.Now()
+	matches := int64(len(re.FindAllIndex(text, -1)))
+	elapsed := time.Since(start)
+
+	benchlib.Report("regex", "match-1mb", matches, elapsed)
+	if benchlib.Seed() == benchlib.DefaultSeed && matches != expectedMatches {
+		benchlib.ReportErr("regex", "match-1mb", expectedMatches, matches)
+		benchlib.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+// Ring Benchmark - Go implementation
+// Output format: BENCH:ring:<test>:<result>:<time_ms>:<time_ns>
+//
+// The threadring benchmark: -n goroutines (503 by default) are linked in
+// a ring by unbuffered channels, and a token goes around the ring -m
+// times (10,000 by default). Every hop adds one to the token, so the
+// result is the final token value, n*m, and a -throughput line reports
+// token passes per second. Only one goroutine is ever runnable, so this
+// measures the cost of a single channel handoff between goroutines.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// node forwards the token from in to out, adding one each time. The node
+// that receives the final value reports it on done and closes out; every
+// later node sees its input closed and closes its own output, so the whole
+// ring shuts down.
+func node(in <-chan int64, out chan<- int64, done chan<- int64, last int64) {
+	defer close(out)
+	for tok := range in {
+		if tok == last {
+			done <- tok
+			return
+		}
+		out <- tok + 1
+	}
+}
+
+// passToken builds a ring of n nodes, sends the token around it until it
+// has made hops passes, and returns its final value.
+func passToken(n int, hops int64) int64 {
+	ring := make([]chan int64, n)
+	for i := range ring {
+		ring[i] = make(chan int64)
+	}
+	done := make(chan int64, 1)
+	for i := range ring {
+		go node(ring[i], ring[(i+1)%n], done, hops)
+	}
+	ring[0] <- 0
+	return <-done
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int("n", 503, "number of goroutines in the ring")
+	m := flag.Int64("m", 10000, "number of times the token goes around the ring")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *n < 2 {
+		fmt.Fprintf(os.Stderr, "ring: -n must be at least 2, got %d\n", *n)
+		os.Exit(2)
+	}
+	if *m < 1 {
+		fmt.Fprintf(os.Stderr, "ring: -m must be at least 1, got %d\n", *m)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	hops := int64(*n) * *m
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	tok := passToken(*n, hops)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	benchlib.ReportWithCPU("ring", "token-pass", tok, elapsed, cpuTime)
+	benchlib.ReportRate("ring", "token-pass", hops, "passes", elapsed)
+	if tok != hops {
+		benchlib.ReportErr("ring", "token-pass", hops, tok)
+	}
+	leaks.Verify("ring")
+}
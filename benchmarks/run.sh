@@ -11,7 +11,7 @@ set -e
 cd "$(dirname "$0")"
 
 # Configuration
-BENCHMARKS="fibonacci collections primes skynet pingpong fanout"
+BENCHMARKS="fibonacci sum_squares collections primes matmul linsolve mandelbrot strings ackermann nbody binarytrees leibniz_pi regex wordcount panic defer dispatch generics io collatz spigot editdistance knapsack json hash stack skynet pingpong fanout pipeline cancel sync spawn channel ring chameneos philosophers time"
 LANGUAGES="seq python go rust"
 RESULTS_DIR="results"
 SEQC="../target/release/seqc"
@@ -56,30 +56,36 @@ run_bench() {
     local bench=$1
     local lang=$2
     local output_file="$RESULTS_DIR/${bench}_${lang}.txt"
+    local src
+    case $lang in
+        seq) src="$bench/seq.seq" ;;
+        python) src="$bench/python.py" ;;
+        go) src="$bench/go.go" ;;
+        rust) src="$bench/rust.rs" ;;
+    esac
+
+    # Not every benchmark is implemented in every language
+    [ -f "$src" ] || { echo "SKIP:$bench:$lang:no implementation" > "$output_file"; return; }
 
     case $lang in
         seq)
             [ "$HAS_SEQ" = false ] && { echo "SKIP:$bench:$lang:seqc not available" > "$output_file"; return; }
-            local src="$bench/seq.seq"
             local bin="/tmp/bench_${bench}_seq"
-            [ -f "$src" ] && "$SEQC" build "$src" -o "$bin" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
+            "$SEQC" build "$src" -o "$bin" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
             ;;
         python)
             [ "$HAS_PYTHON" = false ] && { echo "SKIP:$bench:$lang:python3 not available" > "$output_file"; return; }
-            local src="$bench/python.py"
-            [ -f "$src" ] && python3 "$src" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
+            python3 "$src" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
             ;;
         go)
             [ "$HAS_GO" = false ] && { echo "SKIP:$bench:$lang:go not available" > "$output_file"; return; }
-            local src="$bench/go.go"
             local bin="/tmp/bench_${bench}_go"
-            [ -f "$src" ] && go build -o "$bin" "$src" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
+            go build -o "$bin" "$src" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
             ;;
         rust)
             [ "$HAS_RUST" = false ] && { echo "SKIP:$bench:$lang:rustc not available" > "$output_file"; return; }
-            local src="$bench/rust.rs"
             local bin="/tmp/bench_${bench}_rust"
-            [ -f "$src" ] && rustc -O -o "$bin" "$src" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
+            rustc -O -o "$bin" "$src" 2>/dev/null && "$bin" > "$output_file" 2>&1 || echo "ERROR:$bench:$lang:failed" > "$output_file"
             ;;
     esac
 }
@@ -112,7 +118,9 @@ get_time() {
     local suite=$1 test=$2 lang=$3
     local file="$RESULTS_DIR/${suite}_${lang}.txt"
     [ -f "$file" ] || { echo "-"; return; }
-    local time=$(grep "^BENCH:${suite}:${test}:" "$file" 2>/dev/null | cut -d: -f5)
+    # Multi-run benchmarks report <test>-min/-med/-max; show the median.
+    # Tests with -parallelism are named <test>-p<n>-g<gomaxprocs>.
+    local time=$(grep "^BENCH:${suite}:${test}\(-med\|-p[0-9]*-g[0-9]*\)\?:" "$file" 2>/dev/null | head -1 | cut -d: -f5)
     [ -n "$time" ] && echo "${time} ms" || echo "-"
 }
 
@@ -137,12 +145,44 @@ print_table() {
     echo
 }
 
-print_table "fibonacci" "fib-naive-30" "fib-naive-35" "fib-fast-30" "fib-fast-50" "fib-naive-20-x1000" "fib-fast-20-x1000"
-print_table "collections" "build-100k" "map-double" "filter-evens" "fold-sum" "chain"
-print_table "primes" "count-10k" "count-100k"
+print_table "fibonacci" "fib-naive-30" "fib-naive-35" "fib-fast-30" "fib-fast-50" "fib-tailrec-50" "fib-memo-40" "fib-big-1000" "fib-naive-20-x1000" "fib-fast-20-x1000"
+print_table "sum_squares" "sum-squares-1m"
+print_table "collections" "build-100k" "map-double" "map-double-parallel" "filter-evens" "fold-sum" "chain" "reverse-100k" "rotate-100k" "shuffle-100k" "sort-100k" "binsearch-100k" "map-insert-100k" "map-lookup-100k" "list-sum-100k" "append-grow-1m" "append-prealloc-1m" "aos-sum" "soa-sum"
+print_table "primes" "count-10k" "count-100k" "sieve-100k" "max-gap-1m" "nth-100k"
+print_table "matmul" "multiply-256"
+print_table "linsolve" "gauss-128"
+print_table "mandelbrot" "escape-800x600"
+print_table "strings" "builder-100k" "concat-100k"
+print_table "ackermann" "ack-3-7" "ack-2-1000"
+print_table "nbody" "energy-1m"
+print_table "binarytrees" "depth-18"
+print_table "leibniz_pi" "leibniz-pi-100m" "leibniz-pi-kahan-100m"
+print_table "regex" "match-1mb"
+print_table "wordcount" "tokenize-200k" "count-200k"
+print_table "panic" "recover-loop" "error-return"
+print_table "defer" "with" "without"
+print_table "dispatch" "interface" "concrete"
+print_table "generics" "generic-sum" "mono-sum"
+print_table "io" "bufio-write-100mb" "direct-write-100mb" "bufio-devnull-100mb" "direct-devnull-100mb"
+print_table "collatz" "max-steps-1m"
+print_table "spigot" "pi-digits-1000"
+print_table "editdistance" "levenshtein-2000"
+print_table "knapsack" "best-1000x5000"
+print_table "json" "encode-1k-x100" "decode-1k-x100"
+print_table "hash" "sha256-16mb-x10"
+print_table "stack" "deep-recursion-1m"
 print_table "skynet" "spawn-100k"
 print_table "pingpong" "roundtrip-100k"
 print_table "fanout" "throughput-100k"
+print_table "pipeline" "3stage-100k"
+print_table "cancel" "propagate-100k"
+print_table "sync" "mutex-counter" "atomic-counter" "cas-counter" "syncmap" "rwmutex-map"
+print_table "spawn" "empty-1m"
+print_table "channel" "buffered-send" "unbuffered-send" "churn-1m"
+print_table "ring" "token-pass"
+print_table "chameneos" "meet-3" "meet-10"
+print_table "philosophers" "ordered-5"
+print_table "time" "timer-storm"
 
 echo -e "${CYAN}Note: Python concurrency uses asyncio (cooperative, single-threaded).${NC}"
 echo -e "${CYAN}      Go/Seq/Rust use lightweight threads or OS threads.${NC}"
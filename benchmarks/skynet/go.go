@@ -1,46 +1,176 @@
 // Skynet Benchmark - Go implementation
-// Output format: BENCH:skynet:<test>:<result>:<time_ms>
+// Output format: BENCH:skynet:<test>:<result>:<time_ms>:<time_ns>
 //
-// Spawns goroutines in a 10-ary tree structure.
-// 100,000 goroutines total.
-// Expected result: sum of 0..99999 = 4999950000
+// Spawns goroutines in an arity-ary tree structure (10 by default).
+// -size leaves total (100,000 by default), which must be a power of -arity.
+// Expected result: sum of 0..size-1 (4999950000 for the default size)
+//
+// spawn-only and collect-only split the same work into two timed phases:
+// first the whole tree is spawned with leaves held at a gate, then the
+// gate opens and results propagate back to the root.
+//
+// With -cold the spawn-<size> tree is built BENCH_RUNS times (at least
+// two) and reported as spawn-<size>-cold, the first run, and
+// spawn-<size>-steady, the mean of the rest, instead of a single line.
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"sync"
 	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
 )
 
-func skynet(result chan<- int64, num, size int64) {
+func skynet(result chan<- int64, num, size, arity int64) {
+	if size == 1 {
+		result <- num
+		return
+	}
+
+	children := make(chan int64, arity)
+	childSize := size / arity
+
+	for i := int64(0); i < arity; i++ {
+		go skynet(children, num+i*childSize, childSize, arity)
+	}
+
+	var sum int64
+	for i := int64(0); i < arity; i++ {
+		sum += <-children
+	}
+
+	result <- sum
+}
+
+// gatedTree holds the shared state for the two-phase variant.
+type gatedTree struct {
+	arity int64
+	// spawned counts down once per goroutine as it finishes spawning
+	spawned sync.WaitGroup
+	// gate is closed to release the leaves
+	gate chan struct{}
+}
+
+// nodes returns the number of goroutines in a tree with size leaves.
+func (t *gatedTree) nodes(size int64) int64 {
+	if size == 1 {
+		return 1
+	}
+	return 1 + t.arity*t.nodes(size/t.arity)
+}
+
+func (t *gatedTree) skynet(result chan<- int64, num, size int64) {
 	if size == 1 {
+		t.spawned.Done()
+		<-t.gate
 		result <- num
 		return
 	}
 
-	children := make(chan int64, 10)
-	childSize := size / 10
+	children := make(chan int64, t.arity)
+	childSize := size / t.arity
 
-	for i := int64(0); i < 10; i++ {
-		go skynet(children, num+i*childSize, childSize)
+	for i := int64(0); i < t.arity; i++ {
+		go t.skynet(children, num+i*childSize, childSize)
 	}
+	t.spawned.Done()
 
 	var sum int64
-	for i := 0; i < 10; i++ {
+	for i := int64(0); i < t.arity; i++ {
 		sum += <-children
 	}
 
 	result <- sum
 }
 
+// isPowerOf reports whether size is arity^k for some k >= 0. The recursion
+// splits size by arity at every level, so any other size loses leaves to
+// integer division.
+func isPowerOf(size, arity int64) bool {
+	for size > 1 && size%arity == 0 {
+		size /= arity
+	}
+	return size == 1
+}
+
 func main() {
-	start := time.Now()
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency", "gc")
 
-	result := make(chan int64)
-	go skynet(result, 0, 100000)
+	size := flag.Int64("size", 100000, "number of leaf goroutines")
+	arity := flag.Int64("arity", 10, "children per tree node")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	cold := flag.Bool("cold", false, "report the first run separately from the mean of later runs")
+	flag.Parse()
 
-	sum := <-result
+	if *arity < 2 || !isPowerOf(*size, *arity) {
+		fmt.Fprintf(os.Stderr, "skynet: -size=%d must be a power of -arity=%d (arity >= 2)\n", *size, *arity)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	test := "spawn-" + benchlib.SizeName(*size)
+	expected := *size * (*size - 1) / 2
+	if *cold {
+		var sum int64
+		samples := benchlib.RunN(max(benchlib.Runs(), 2), func() {
+			result := make(chan int64)
+			go skynet(result, 0, *size, *arity)
+			sum = <-result
+		})
+		benchlib.ReportColdSteady("skynet", test, sum, samples)
+		if sum != expected {
+			benchlib.ReportErr("skynet", test, expected, sum)
+		}
+	} else {
+		cpu := benchlib.StartCPU()
+		start := time.Now()
 
-	elapsed := time.Since(start).Milliseconds()
+		result := make(chan int64)
+		go skynet(result, 0, *size, *arity)
 
-	fmt.Printf("BENCH:skynet:spawn-100k:%d:%d\n", sum, elapsed)
+		sum := <-result
+
+		elapsed := time.Since(start)
+		cpuTime := cpu.Stop()
+
+		benchlib.ReportWithCPU("skynet", test, sum, elapsed, cpuTime)
+		if sum != expected {
+			benchlib.ReportErr("skynet", test, expected, sum)
+		}
+	}
+
+	// Two-phase variant: spawn everything, then collect
+	t := &gatedTree{arity: *arity, gate: make(chan struct{})}
+	nodes := t.nodes(*size)
+	t.spawned.Add(int(nodes))
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	result := make(chan int64)
+	go t.skynet(result, 0, *size)
+	t.spawned.Wait()
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+	benchlib.ReportWithCPU("skynet", "spawn-only", nodes, elapsed, cpuTime)
+
+	cpu = benchlib.StartCPU()
+	start = time.Now()
+	close(t.gate)
+	sum := <-result
+	elapsed = time.Since(start)
+	cpuTime = cpu.Stop()
+	benchlib.ReportWithCPU("skynet", "collect-only", sum, elapsed, cpuTime)
+	if sum != expected {
+		benchlib.ReportErr("skynet", "collect-only", expected, sum)
+	}
+	leaks.Verify("skynet")
 }
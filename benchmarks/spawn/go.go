@@ -0,0 +1,67 @@
+// Spawn Benchmark - Go implementation
+// Output format: BENCH:spawn:<test>:<result>:<time_ms>:<time_ns>
+//
+// Starts -n empty goroutines (1,000,000 by default) and joins them with a
+// WaitGroup, isolating goroutine creation and teardown from the channel
+// traffic that skynet mixes in. The result is the number of goroutines
+// that ran, and a -throughput line reports goroutines per second.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+func spawnEmpty(n int64) int64 {
+	var ran atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(int(n))
+	for i := int64(0); i < n; i++ {
+		go func() {
+			ran.Add(1)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	return ran.Load()
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int64("n", 1000000, "number of goroutines to spawn")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "spawn: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	ran := spawnEmpty(*n)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	test := "empty-" + benchlib.SizeName(*n)
+	benchlib.ReportWithCPU("spawn", test, ran, elapsed, cpuTime)
+	benchlib.ReportRate("spawn", test, ran, "goroutines", elapsed)
+	if ran != *n {
+		benchlib.ReportErr("spawn", test, *n, ran)
+		benchlib.Exit(1)
+	}
+	leaks.Verify("spawn")
+}
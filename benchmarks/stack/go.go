@@ -0,0 +1,70 @@
+// Stack Benchmark - Go implementation
+// Output format: BENCH:stack:<test>:<result>:<time_ms>:<time_ns>
+//
+// Recurses -depth levels (1,000,000 by default) through a function that
+// can't be inlined and adds to its callee's result, so every level keeps a
+// real frame and nothing can be turned into a loop. Each run starts on a
+// fresh goroutine, whose small initial stack the runtime has to grow (by
+// copying) over and over on the way down; other runtimes may instead use a
+// fixed-size stack or overflow. The result is the depth reached, which
+// must equal -depth, and the returned sum must be depth(depth+1)/2.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// descend records each level it reaches in *reached, a side effect that
+// keeps the recursion honest, and returns the sum of the levels from here
+// to depth.
+//
+//go:noinline
+func descend(level, depth int64, reached *int64) int64 {
+	*reached = level
+	if level == depth {
+		return level
+	}
+	return level + descend(level+1, depth, reached)
+}
+
+// recurse runs descend on a new goroutine and returns the depth it
+// reached and its sum.
+func recurse(depth int64) (reached, sum int64) {
+	done := make(chan struct{})
+	go func() {
+		sum = descend(1, depth, &reached)
+		close(done)
+	}()
+	<-done
+	return reached, sum
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu")
+
+	depth := flag.Int64("depth", 1000000, "recursion depth")
+	flag.Parse()
+	if *depth < 1 {
+		fmt.Fprintf(os.Stderr, "stack: -depth must be at least 1, got %d\n", *depth)
+		os.Exit(2)
+	}
+	benchlib.ReportEnv()
+
+	test := "deep-recursion-" + benchlib.SizeName(*depth)
+	var reached, sum int64
+	samples := benchlib.RunN(benchlib.Runs(), func() {
+		reached, sum = recurse(*depth)
+	})
+	benchlib.ReportRuns("stack", test, reached, samples)
+	if reached != *depth {
+		benchlib.ReportErr("stack", test, *depth, reached)
+	}
+	if want := *depth * (*depth + 1) / 2; sum != want {
+		benchlib.Failf("stack", test, "sum of levels is %d, want %d", sum, want)
+	}
+}
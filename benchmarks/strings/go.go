@@ -0,0 +1,60 @@
+// Strings Benchmark - Go implementation
+// Output format: BENCH:strings:<test>:<result>:<time_ms>:<time_ns>
+//
+// Builds one large string from many small pieces, once with
+// strings.Builder (linear) and once with naive += concatenation
+// (quadratic, since every append copies the whole string so far).
+// The result is the final string length.
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const numPieces = 100000
+
+// piece returns the i-th fragment appended by both builders.
+func piece(i int) string {
+	return string(rune('a' + i%26))
+}
+
+func buildWithBuilder() string {
+	var b strings.Builder
+	for i := 0; i < numPieces; i++ {
+		b.WriteString(piece(i))
+	}
+	return b.String()
+}
+
+func buildWithConcat() string {
+	s := ""
+	for i := 0; i < numPieces; i++ {
+		s += piece(i)
+	}
+	return s
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("cpu", "gc")
+
+	benchlib.ReportEnv()
+
+	start := time.Now()
+	built := buildWithBuilder()
+	elapsed := time.Since(start)
+	benchlib.Report("strings", "builder-100k", int64(len(built)), elapsed)
+
+	start = time.Now()
+	concatenated := buildWithConcat()
+	elapsed = time.Since(start)
+	benchlib.Report("strings", "concat-100k", int64(len(concatenated)), elapsed)
+
+	if len(built) != len(concatenated) {
+		benchlib.ReportErr("strings", "concat-100k", int64(len(built)), int64(len(concatenated)))
+		benchlib.Exit(1)
+	}
+}
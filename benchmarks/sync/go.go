@@ -0,0 +1,285 @@
+// Sync Benchmark - Go implementation
+// Output format: BENCH:sync:<test>:<result>:<time_ms>:<time_ns>
+//
+// numWorkers goroutines increment one shared counter numMessages times in
+// total, once guarded by a sync.Mutex, once with an atomic.Int64 and once
+// with a compare-and-swap retry loop on an atomic.Int64. Contrasts
+// shared-memory synchronization with the channel benchmarks.
+//
+// cas-counter also reports successful CAS operations per second on a
+// -throughput line, the total failed attempts on a -retries line and those
+// per 1000 successes on a -retry-permille line. Retries need
+// goroutines running in parallel, so expect almost none with GOMAXPROCS=1.
+//
+// syncmap and rwmutex-map run the same mixed workload, numMessages loads and
+// stores split by -ratio (9:1 reads to writes by default), against a
+// sync.Map and an RWMutex-guarded map. Which keys get written doesn't
+// depend on scheduling, so both must end with the same key set; the result
+// is its size.
+//
+// The atomic-contention tests, atomic-counter and cas-counter, spread the
+// increments over -parallelism goroutines (numWorkers by default), set
+// independently of the -procs GOMAXPROCS override, and are reported as
+// <test>-p<parallelism>-g<gomaxprocs>.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+const numMessages = 1000000
+const numWorkers = 10
+
+// mapKeys is the key space of the concurrent-map tests.
+const mapKeys = 1 << 16
+
+// hammer runs workers goroutines that together call inc numMessages
+// times, waits for them to finish, and returns the sum of inc's results.
+func hammer(workers int, inc func() int64) int64 {
+	var wg sync.WaitGroup
+	sums := make([]int64, workers)
+	for w := 0; w < workers; w++ {
+		// The first numMessages%workers goroutines take one extra call
+		calls := numMessages / workers
+		if w < numMessages%workers {
+			calls++
+		}
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			var sum int64
+			for i := 0; i < calls; i++ {
+				sum += inc()
+			}
+			sums[w] = sum
+		}(w)
+	}
+	wg.Wait()
+	var total int64
+	for _, sum := range sums {
+		total += sum
+	}
+	return total
+}
+
+// casIncrement adds one to counter by compare-and-swap, retrying until no
+// other goroutine got in between the load and the swap, and returns the
+// number of failed attempts.
+func casIncrement(counter *atomic.Int64) int64 {
+	var retries int64
+	for {
+		old := counter.Load()
+		if counter.CompareAndSwap(old, old+1) {
+			return retries
+		}
+		retries++
+	}
+}
+
+// concurrentMap is the part of a goroutine-safe map the mixed workload
+// needs.
+type concurrentMap interface {
+	load(k int64) (int64, bool)
+	store(k, v int64)
+	// keys returns the stored keys in ascending order.
+	keys() []int64
+}
+
+type syncMap struct{ m sync.Map }
+
+func (s *syncMap) load(k int64) (int64, bool) {
+	v, ok := s.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int64), true
+}
+
+func (s *syncMap) store(k, v int64) { s.m.Store(k, v) }
+
+func (s *syncMap) keys() []int64 {
+	var ks []int64
+	s.m.Range(func(k, _ any) bool {
+		ks = append(ks, k.(int64))
+		return true
+	})
+	slices.Sort(ks)
+	return ks
+}
+
+type rwMap struct {
+	mu sync.RWMutex
+	m  map[int64]int64
+}
+
+func (r *rwMap) load(k int64) (int64, bool) {
+	r.mu.RLock()
+	v, ok := r.m[k]
+	r.mu.RUnlock()
+	return v, ok
+}
+
+func (r *rwMap) store(k, v int64) {
+	r.mu.Lock()
+	r.m[k] = v
+	r.mu.Unlock()
+}
+
+func (r *rwMap) keys() []int64 {
+	ks := make([]int64, 0, len(r.m))
+	for k := range r.m {
+		ks = append(ks, k)
+	}
+	slices.Sort(ks)
+	return ks
+}
+
+// mixed runs numWorkers goroutines that together make numMessages calls on
+// m. Operation n touches a key scattered from n and is a store for the last
+// writes of every reads+writes operations, a load otherwise, so the keys
+// stored are the same however the goroutines interleave.
+func mixed(m concurrentMap, reads, writes int) {
+	var wg sync.WaitGroup
+	perWorker := numMessages / numWorkers
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				n := w*perWorker + i
+				k := int64(n) * 2654435761 % mapKeys
+				if n%(reads+writes) >= reads {
+					m.store(k, int64(n))
+				} else {
+					m.load(k)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// parseRatio parses a reads:writes ratio such as 9:1.
+func parseRatio(s string) (reads, writes int, err error) {
+	r, w, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("want reads:writes, got %q", s)
+	}
+	if reads, err = strconv.Atoi(r); err != nil || reads < 0 {
+		return 0, 0, fmt.Errorf("bad read count in %q", s)
+	}
+	if writes, err = strconv.Atoi(w); err != nil || writes < 1 {
+		return 0, 0, fmt.Errorf("write count in %q must be at least 1", s)
+	}
+	return reads, writes, nil
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	ratio := flag.String("ratio", "9:1", "reads:writes mix of the concurrent-map tests")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	parallelism := flag.Int("parallelism", numWorkers, "goroutines for the atomic-counter and cas-counter tests")
+	flag.Parse()
+	reads, writes, err := parseRatio(*ratio)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sync: -ratio: %v\n", err)
+		os.Exit(2)
+	}
+	if *parallelism < 1 {
+		fmt.Fprintf(os.Stderr, "sync: -parallelism must be at least 1, got %d\n", *parallelism)
+		os.Exit(2)
+	}
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	// Mutex-guarded counter
+	var mu sync.Mutex
+	var locked int64
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	hammer(numWorkers, func() int64 {
+		mu.Lock()
+		locked++
+		mu.Unlock()
+		return 0
+	})
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+	benchlib.ReportWithCPU("sync", "mutex-counter", locked, elapsed, cpuTime)
+	if locked != numMessages {
+		benchlib.ReportErr("sync", "mutex-counter", numMessages, locked)
+	}
+
+	// Atomic counter
+	test := benchlib.ParallelName("atomic-counter", *parallelism)
+	var counter atomic.Int64
+	cpu = benchlib.StartCPU()
+	start = time.Now()
+	hammer(*parallelism, func() int64 {
+		counter.Add(1)
+		return 0
+	})
+	elapsed = time.Since(start)
+	cpuTime = cpu.Stop()
+	benchlib.ReportWithCPU("sync", test, counter.Load(), elapsed, cpuTime)
+	if counter.Load() != numMessages {
+		benchlib.ReportErr("sync", test, numMessages, counter.Load())
+	}
+
+	// Compare-and-swap counter
+	test = benchlib.ParallelName("cas-counter", *parallelism)
+	var swapped atomic.Int64
+	cpu = benchlib.StartCPU()
+	start = time.Now()
+	retries := hammer(*parallelism, func() int64 { return casIncrement(&swapped) })
+	elapsed = time.Since(start)
+	cpuTime = cpu.Stop()
+	successes := swapped.Load()
+	benchlib.ReportWithCPU("sync", test, successes, elapsed, cpuTime)
+	benchlib.ReportRate("sync", test, successes, "cas", elapsed)
+	benchlib.Report("sync", test+"-retries", retries, 0)
+	benchlib.Report("sync", test+"-retry-permille", retries*1000/numMessages, 0)
+	benchlib.Notef("Retries: %d (%.3f per successful CAS)", retries, float64(retries)/numMessages)
+	if successes != numMessages {
+		benchlib.ReportErr("sync", test, numMessages, successes)
+	}
+
+	// Concurrent maps under a mixed read/write load
+	var keySets [][]int64
+	for _, tc := range []struct {
+		test string
+		m    concurrentMap
+	}{
+		{"syncmap", &syncMap{}},
+		{"rwmutex-map", &rwMap{m: make(map[int64]int64)}},
+	} {
+		cpu = benchlib.StartCPU()
+		start = time.Now()
+		mixed(tc.m, reads, writes)
+		elapsed = time.Since(start)
+		cpuTime = cpu.Stop()
+		keys := tc.m.keys()
+		benchlib.ReportWithCPU("sync", tc.test, int64(len(keys)), elapsed, cpuTime)
+		keySets = append(keySets, keys)
+	}
+	if !slices.Equal(keySets[0], keySets[1]) {
+		benchlib.Failf("sync", "rwmutex-map", "key set differs from syncmap's (%d keys, syncmap %d)",
+			len(keySets[1]), len(keySets[0]))
+	}
+
+	leaks.Verify("sync")
+}
@@ -0,0 +1,97 @@
+// Time Benchmark - Go implementation
+// Output format: BENCH:time:<test>:<result>:<time_ms>:<time_ns>
+//
+// timer-storm arms -n timers (100,000 by default) with time.AfterFunc,
+// each for a duration below -max-delay (1ms by default) drawn from the
+// shared LCG (seed from BENCH_SEED), and waits for all of them to fire.
+// That loads the runtime's timer heaps rather than any one channel. Every
+// timer counts its firings; the result is the total, which must be n with
+// each timer firing exactly once, and a -throughput line reports timers
+// fired per second.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/navicore/patch-seq/benchmarks/internal/benchlib"
+)
+
+// fireGrace is how long past -max-delay the storm may take before the
+// timers that haven't fired are reported.
+const fireGrace = 10 * time.Second
+
+// storm arms a timer for each of delays and waits until they have all
+// fired or timeout passes. It returns the total firings and each timer's
+// count.
+func storm(delays []time.Duration, timeout time.Duration) (int64, []atomic.Int32) {
+	fired := make([]atomic.Int32, len(delays))
+	var total atomic.Int64
+	done := make(chan struct{})
+	n := int64(len(delays))
+	for i, d := range delays {
+		time.AfterFunc(d, func() {
+			fired[i].Add(1)
+			if total.Add(1) == n {
+				close(done)
+			}
+		})
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+	return total.Load(), fired
+}
+
+func main() {
+	defer benchlib.Finish()
+	benchlib.Declare("concurrency")
+
+	n := flag.Int("n", 100000, "number of timers")
+	maxDelay := flag.Duration("max-delay", time.Millisecond, "timers fire after a random duration below this")
+	procs := flag.Int("procs", 0, "GOMAXPROCS override (0 keeps the default)")
+	leakcheck := flag.Bool("leakcheck", false, "fail if goroutines are still running when the benchmark ends")
+	cpuset := flag.String("cpuset", "", "pin the process to these CPUs, e.g. 0,1 or 0-3 (Linux only)")
+	flag.Parse()
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "time: -n must be at least 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	if *maxDelay <= 0 {
+		fmt.Fprintf(os.Stderr, "time: -max-delay must be positive, got %v\n", *maxDelay)
+		os.Exit(2)
+	}
+	benchlib.SetupCPUSet(*cpuset)
+	benchlib.SetupProcs(*procs)
+	benchlib.ReportEnv()
+	leaks := benchlib.StartLeakCheck(*leakcheck)
+
+	rng := benchlib.NewLCG(benchlib.Seed())
+	delays := make([]time.Duration, *n)
+	for i := range delays {
+		delays[i] = time.Duration((rng.Next() >> 33) % uint64(*maxDelay))
+	}
+
+	cpu := benchlib.StartCPU()
+	start := time.Now()
+	total, fired := storm(delays, *maxDelay+fireGrace)
+	elapsed := time.Since(start)
+	cpuTime := cpu.Stop()
+
+	benchlib.ReportWithCPU("time", "timer-storm", total, elapsed, cpuTime)
+	benchlib.ReportRate("time", "timer-storm", total, "timers", elapsed)
+	if total != int64(*n) {
+		benchlib.ReportErr("time", "timer-storm", int64(*n), total)
+	}
+	for i := range fired {
+		if c := fired[i].Load(); c != 1 {
+			benchlib.Failf("time", "timer-storm", "timer %d fired %d times", i, c)
+			break
+		}
+	}
+	leaks.Verify("time")
+}